@@ -0,0 +1,309 @@
+package schuko
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Decode fills a struct from a configuration. target must be a non-nil pointer
+// to a struct. Keys are searched for relative to prefix, which may be empty.
+//
+// Struct fields are mapped to configuration keys by tag "schuko". Fields without
+// a tag are mapped to their lower-cased field name, fields tagged with "-" are
+// skipped. A tag may carry options, separated by commas; currently the only
+// option is "required", unknown options are ignored. Tag "default" provides a
+// value which is used if the key is not set. Example:
+//
+//	type Settings struct {
+//	    Port    int           `schuko:"server.port,required"`
+//	    Timeout time.Duration `schuko:"server.timeout" default:"30s"`
+//	    Hosts   []string      `schuko:"hosts"`
+//	    DB      struct {
+//	        User     string `schuko:"user" default:"admin"`
+//	        Password *string
+//	    } `schuko:"db"`
+//	}
+//
+// Nested structs use their key as a prefix for their fields. A nested struct
+// tagged "required" must have at least one key set below its prefix. Pointers
+// to nested structs are allocated only if a key below their prefix is set;
+// otherwise the pointer stays nil and neither defaults nor required fields of
+// the struct apply. Embedded structs without a tag share the prefix of the
+// surrounding struct. Besides Go base types,
+// Decode supports time.Duration, time.Time, pointers, slices and maps with string
+// keys. Conversion of values follows the rules of ToInt, ToDuration, etc.
+//
+// Decode works on any Configuration, using Strict(conf) for lookups. It will
+// process every field and report all missing required keys and all conversion
+// errors at once, by returning a *DecodeError.
+func Decode(conf Configuration, prefix string, target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("decode target must be a non-nil pointer to a struct, is %T", target)
+	}
+	d := &decoder{conf: Strict(conf)}
+	d.decodeStruct(v.Elem(), prefix)
+	if len(d.err.Missing) > 0 || len(d.err.Invalid) > 0 {
+		return &d.err
+	}
+	return nil
+}
+
+// DecodeError is returned by Decode. It collects all missing required keys and all
+// invalid values found during decoding.
+type DecodeError struct {
+	Missing []string // missing required keys
+	Invalid []error  // conversion errors, usually of type *LookupError
+}
+
+func (e *DecodeError) Error() string {
+	var b strings.Builder
+	if len(e.Missing) > 0 {
+		b.WriteString("missing required configuration keys: ")
+		b.WriteString(strings.Join(e.Missing, ", "))
+	}
+	for _, err := range e.Invalid {
+		if b.Len() > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap makes DecodeError usable with errors.Is and errors.As.
+// If required keys are missing, ErrKeyNotFound is part of the error chain.
+func (e *DecodeError) Unwrap() []error {
+	errs := e.Invalid
+	if len(e.Missing) > 0 {
+		errs = append([]error{ErrKeyNotFound}, errs...)
+	}
+	return errs
+}
+
+type decoder struct {
+	conf StrictConfiguration
+	err  DecodeError
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// decodeStruct decodes all fields of struct v. It returns true if at least one
+// field has been set from configuration, i.e. not from a default value.
+func (d *decoder) decodeStruct(v reflect.Value, prefix string) bool {
+	isSet := false
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, hasTag := field.Tag.Lookup("schuko")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		required := false
+		for _, opt := range strings.Split(opts, ",") {
+			required = required || strings.TrimSpace(opt) == "required"
+		}
+		fv := v.Field(i)
+		if field.Anonymous && !hasTag && field.Type.Kind() == reflect.Struct {
+			isSet = d.decodeStruct(fv, prefix) || isSet
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		key := joinKey(prefix, name)
+		if isStruct(field.Type) {
+			isSet = d.decodeNested(fv, key, required) || isSet
+			continue
+		}
+		ok, err := d.assign(fv, d.conf, key)
+		if err == nil && !ok {
+			if def, hasDefault := field.Tag.Lookup("default"); hasDefault {
+				_, err = d.assign(fv, Strict(valueConf{key: key, value: def}), key)
+			} else if required {
+				d.err.Missing = append(d.err.Missing, key)
+			}
+		}
+		if err != nil {
+			d.err.Invalid = append(d.err.Invalid, err)
+		}
+		isSet = ok || isSet
+	}
+	return isSet
+}
+
+// decodeNested decodes a struct or pointer to struct. Pointers are allocated only
+// if at least one field of the struct is set from configuration. If the struct
+// is required, but no key below key is set, key is reported missing.
+func (d *decoder) decodeNested(fv reflect.Value, key string, required bool) bool {
+	if fv.Kind() != reflect.Pointer {
+		isSet := d.decodeStruct(fv, key)
+		if required && !isSet {
+			d.err.Missing = append(d.err.Missing, key)
+		}
+		return isSet
+	}
+	sub := &decoder{conf: d.conf}
+	s := reflect.New(fv.Type().Elem())
+	if !sub.decodeStruct(s.Elem(), key) && len(sub.err.Invalid) == 0 {
+		if required {
+			d.err.Missing = append(d.err.Missing, key)
+		}
+		return false
+	}
+	fv.Set(s)
+	d.err.Missing = append(d.err.Missing, sub.err.Missing...)
+	d.err.Invalid = append(d.err.Invalid, sub.err.Invalid...)
+	return true
+}
+
+// assign looks up key and sets fv accordingly. It returns false if key is not
+// set in configuration conf.
+func (d *decoder) assign(fv reflect.Value, conf StrictConfiguration, key string) (bool, error) {
+	var x any
+	var err error
+	t := fv.Type()
+	switch {
+	case t == durationType:
+		x, err = conf.LookupDuration(key)
+	case t == timeType:
+		x, err = conf.LookupTime(key)
+	case t.Kind() == reflect.Pointer:
+		p := reflect.New(t.Elem())
+		ok, err := d.assign(p.Elem(), conf, key)
+		if ok && err == nil {
+			fv.Set(p)
+		}
+		return ok, err
+	case t.Kind() == reflect.String:
+		x, err = conf.LookupString(key)
+	case t.Kind() == reflect.Bool:
+		x, err = conf.LookupBool(key)
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Int64:
+		var n int
+		if n, err = conf.LookupInt(key); err == nil && fv.OverflowInt(int64(n)) {
+			err = &LookupError{Key: key, Type: t.String(), Value: n, Err: ErrInvalidValue,
+				Cause: errors.New("value out of range")}
+		}
+		x = n
+	case t.Kind() >= reflect.Uint && t.Kind() <= reflect.Uint64:
+		var n int
+		if n, err = conf.LookupInt(key); err == nil && (n < 0 || fv.OverflowUint(uint64(n))) {
+			err = &LookupError{Key: key, Type: t.String(), Value: n, Err: ErrInvalidValue,
+				Cause: errors.New("value out of range")}
+		}
+		x = n
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		x, err = conf.LookupFloat64(key)
+	case t.Kind() == reflect.Slice:
+		return d.assignSlice(fv, conf, key)
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		return d.assignMap(fv, conf, key)
+	default:
+		return false, &LookupError{Key: key, Type: t.String(), Err: ErrInvalidValue,
+			Cause: errors.New("type not supported by Decode")}
+	}
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	fv.Set(reflect.ValueOf(x).Convert(t))
+	return true, nil
+}
+
+func (d *decoder) assignSlice(fv reflect.Value, conf StrictConfiguration, key string) (bool, error) {
+	l, err := conf.LookupStringSlice(key)
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	s := reflect.MakeSlice(fv.Type(), len(l), len(l))
+	for i, item := range l {
+		itemKey := fmt.Sprintf("%s[%d]", key, i)
+		if _, err := d.assign(s.Index(i), Strict(valueConf{key: itemKey, value: item}), itemKey); err != nil {
+			return false, err
+		}
+	}
+	fv.Set(s)
+	return true, nil
+}
+
+func (d *decoder) assignMap(fv reflect.Value, conf StrictConfiguration, key string) (bool, error) {
+	m, err := conf.LookupStringMap(key)
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	t := fv.Type()
+	result := reflect.MakeMapWithSize(t, len(m))
+	for k, v := range m {
+		val := reflect.New(t.Elem()).Elem()
+		if t.Elem().Kind() == reflect.Interface {
+			if v != nil {
+				val.Set(reflect.ValueOf(v))
+			}
+		} else {
+			itemKey := joinKey(key, k)
+			item := Strict(valueConf{key: itemKey, value: fmt.Sprintf("%v", v)})
+			if _, err := d.assign(val, item, itemKey); err != nil {
+				return false, err
+			}
+		}
+		result.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), val)
+	}
+	fv.Set(result)
+	return true, nil
+}
+
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != timeType
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// valueConf is a configuration holding a single key. Decode uses it to convert
+// default values and items of lists.
+type valueConf struct {
+	key, value string
+}
+
+func (c valueConf) InitDefaults()         {}
+func (c valueConf) IsSet(key string) bool { return key == c.key }
+func (c valueConf) GetString(key string) string {
+	if key != c.key {
+		return ""
+	}
+	return c.value
+}
+func (c valueConf) GetInt(key string) int {
+	n, _ := ToInt(c.GetString(key))
+	return n
+}
+func (c valueConf) GetBool(key string) bool {
+	b, _ := ToBool(c.GetString(key))
+	return b
+}
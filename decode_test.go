@@ -0,0 +1,118 @@
+package schuko_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/testconfig"
+)
+
+type dbSettings struct {
+	User     string `schuko:"user" default:"admin"`
+	Password *string
+	Pool     uint8 `schuko:"pool.size" default:"4"`
+}
+
+type settings struct {
+	Port    int            `schuko:"server.port,required"`
+	Timeout time.Duration  `schuko:"server.timeout" default:"30s"`
+	Hosts   []string       `schuko:"hosts"`
+	Weights []float64      `schuko:"weights"`
+	Debug   *bool          `schuko:"debug"`
+	Labels  map[string]int `schuko:"labels"`
+	DB      dbSettings     `schuko:"db"`
+	Cache   *dbSettings    `schuko:"cache"`
+	Ignored string         `schuko:"-"`
+}
+
+func TestDecode(t *testing.T) {
+	conf := testconfig.Conf{
+		"app.server.port": "8080",
+		"app.hosts":       "a, b",
+		"app.weights":     "0.5, 1.5",
+		"app.labels":      "x=1, y=2",
+		"app.db.password": "secret",
+		"app.Ignored":     "x",
+	}
+	var s settings
+	if err := schuko.Decode(conf, "app", &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Port != 8080 || s.Timeout != 30*time.Second {
+		t.Errorf("expected port 8080 and default timeout 30s, got %d and %v", s.Port, s.Timeout)
+	}
+	if len(s.Hosts) != 2 || s.Hosts[1] != "b" || len(s.Weights) != 2 || s.Weights[1] != 1.5 {
+		t.Errorf("expected lists to be decoded, got %v and %v", s.Hosts, s.Weights)
+	}
+	if s.Labels["y"] != 2 {
+		t.Errorf("expected labels to be decoded, got %v", s.Labels)
+	}
+	if s.DB.User != "admin" || s.DB.Password == nil || *s.DB.Password != "secret" || s.DB.Pool != 4 {
+		t.Errorf("expected nested struct to be decoded, got %+v", s.DB)
+	}
+	if s.Debug != nil {
+		t.Errorf("expected pointer for unset key to stay nil")
+	}
+	if s.Cache != nil {
+		t.Errorf("expected pointer to struct without keys set to stay nil, got %+v", s.Cache)
+	}
+	conf["app.cache.pool.size"] = "8"
+	if err := schuko.Decode(conf, "app", &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Cache == nil || s.Cache.User != "admin" || s.Cache.Pool != 8 {
+		t.Errorf("expected pointer to struct with a key set to be allocated, got %+v", s.Cache)
+	}
+	if s.Ignored != "" {
+		t.Errorf("expected field tagged '-' to be skipped")
+	}
+}
+
+func TestDecodeAggregatesErrors(t *testing.T) {
+	type required struct {
+		A int    `schuko:"a,required"`
+		B string `schuko:"b,required"`
+		C bool   `schuko:"c"`
+		D int8   `schuko:"d"`
+	}
+	conf := testconfig.Conf{"c": "maybe", "d": "300"}
+	var r required
+	err := schuko.Decode(conf, "", &r)
+	var derr *schuko.DecodeError
+	if !errors.As(err, &derr) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	t.Logf("error: %v", err)
+	if len(derr.Missing) != 2 || derr.Missing[0] != "a" || derr.Missing[1] != "b" {
+		t.Errorf("expected keys a and b to be reported missing, got %v", derr.Missing)
+	}
+	if len(derr.Invalid) != 2 {
+		t.Errorf("expected 2 invalid values, got %v", derr.Invalid)
+	}
+	if !errors.Is(err, schuko.ErrKeyNotFound) || !errors.Is(err, schuko.ErrInvalidValue) {
+		t.Errorf("expected error to match ErrKeyNotFound and ErrInvalidValue")
+	}
+}
+
+func TestDecodeRequiredNested(t *testing.T) {
+	type nested struct {
+		DB    dbSettings  `schuko:"db,required"`
+		Cache *dbSettings `schuko:"cache,required"`
+		Name  string      `schuko:"name,omitempty,required"`
+	}
+	var n nested
+	err := schuko.Decode(testconfig.Conf{}, "", &n)
+	var derr *schuko.DecodeError
+	if !errors.As(err, &derr) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if len(derr.Missing) != 3 || derr.Missing[0] != "db" || derr.Missing[1] != "cache" || derr.Missing[2] != "name" {
+		t.Errorf("expected keys db, cache and name to be reported missing, got %v", derr.Missing)
+	}
+	conf := testconfig.Conf{"db.user": "u", "cache.user": "c", "name": "x"}
+	if err := schuko.Decode(conf, "", &n); err != nil {
+		t.Errorf("expected required nested structs to be satisfied, got %v", err)
+	}
+}
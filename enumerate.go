@@ -0,0 +1,79 @@
+package schuko
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ErrNotEnumerable is returned by operations which need to enumerate the keys
+// of a configuration not implementing EnumerableConfiguration.
+var ErrNotEnumerable = errors.New("configuration cannot enumerate its keys")
+
+// EnumerableConfiguration is an optional extension of Configuration. Adapters
+// implement it to enumerate the keys they hold, enabling diagnostics,
+// migrations or printing of the effective configuration.
+type EnumerableConfiguration interface {
+	Configuration
+	Keys(prefix string) []string // keys of all values set, in sorted order
+	AllSettings() map[string]any // all values set, as a nested dictionary
+}
+
+// MatchesPrefix is a predicate wether key is within the key space denoted by
+// prefix, i.e. key equals prefix or starts with prefix and a separator ('.' or '/').
+// Every key matches an empty prefix.
+//
+// MatchesPrefix is a helper for configuration adapters implementing
+// EnumerableConfiguration.
+func MatchesPrefix(key, prefix string) bool {
+	prefix = strings.TrimRight(prefix, "./")
+	if prefix == "" || key == prefix {
+		return true
+	}
+	if !strings.HasPrefix(key, prefix) {
+		return false
+	}
+	sep := key[len(prefix)]
+	return sep == '.' || sep == '/'
+}
+
+// FilterKeys returns the keys matching prefix (see MatchesPrefix), in sorted order.
+func FilterKeys(keys []string, prefix string) []string {
+	filtered := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if MatchesPrefix(key, prefix) {
+			filtered = append(filtered, key)
+		}
+	}
+	sort.Strings(filtered)
+	return filtered
+}
+
+// Unflatten converts a map of dotted keys to a nested dictionary, e.g.
+// {"db.host": "localhost"} to {"db": {"host": "localhost"}}. If a key is
+// both a value and a prefix of other keys, the nested keys win.
+func Unflatten(flat map[string]any) map[string]any {
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys) // shorter keys first, thus nested keys win
+	m := make(map[string]any)
+	for _, key := range keys {
+		segments := strings.Split(key, ".")
+		node := m
+		for _, seg := range segments[:len(segments)-1] {
+			child, ok := node[seg].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				node[seg] = child
+			}
+			node = child
+		}
+		last := segments[len(segments)-1]
+		if _, isMap := node[last].(map[string]any); !isMap {
+			node[last] = flat[key]
+		}
+	}
+	return m
+}
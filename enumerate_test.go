@@ -0,0 +1,45 @@
+package schuko_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/testconfig"
+)
+
+func TestMatchesPrefix(t *testing.T) {
+	tests := []struct {
+		key, prefix string
+		match       bool
+	}{
+		{"db.host", "", true},
+		{"db.host", "db", true},
+		{"db/host", "db.", true},
+		{"db", "db", true},
+		{"dbx.host", "db", false},
+		{"server.port", "db", false},
+	}
+	for _, tt := range tests {
+		if m := schuko.MatchesPrefix(tt.key, tt.prefix); m != tt.match {
+			t.Errorf("MatchesPrefix(%q, %q) = %v, expected %v", tt.key, tt.prefix, m, tt.match)
+		}
+	}
+}
+
+func TestLayeredEnumeration(t *testing.T) {
+	conf := schuko.NewLayered(
+		schuko.Layer{Name: "high", Conf: testconfig.Conf{"db.host": "db.local"}},
+		schuko.Layer{Name: "low", Conf: testconfig.Conf{"db.host": "localhost", "db.port": 5432, "name": "x"}},
+	)
+	if keys := conf.Keys("db"); !reflect.DeepEqual(keys, []string{"db.host", "db.port"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	expected := map[string]any{
+		"db":   map[string]any{"host": "db.local", "port": 5432},
+		"name": "x",
+	}
+	if all := conf.AllSettings(); !reflect.DeepEqual(all, expected) {
+		t.Errorf("expected settings %v, got %v", expected, all)
+	}
+}
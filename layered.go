@@ -0,0 +1,324 @@
+package schuko
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// Layer is a named configuration, to be stacked onto other configurations by
+// a Layered configuration.
+type Layer struct {
+	Name string        // name of the layer, e.g. "flags", "env", "file", "defaults"
+	Conf Configuration // configuration supplying values for this layer
+}
+
+// Layered is a configuration which consults an ordered list of configurations.
+// Layers are given in order of precedence: the first layer having a key set
+// supplies the value. Any Configuration may act as a layer.
+//
+// An example of a common setup is:
+//
+//	conf := schuko.NewLayered(
+//	    schuko.Layer{Name: "flags", Conf: flagconf},
+//	    schuko.Layer{Name: "env", Conf: envconf},
+//	    schuko.Layer{Name: "file", Conf: fileconf},
+//	    schuko.Layer{Name: "defaults", Conf: testconfig.Conf{"tracing.adapter": "go"}},
+//	)
+//
+// Layered implements TypedConfiguration and StrictConfiguration, independent of
+// the layers implementing them.
+type Layered struct {
+	layers []Layer
+}
+
+// NewLayered creates a layered configuration from layers, ordered from highest
+// to lowest precedence.
+func NewLayered(layers ...Layer) *Layered {
+	return &Layered{layers: layers}
+}
+
+// Layers returns the layers of this configuration, ordered from highest to lowest
+// precedence.
+func (l *Layered) Layers() []Layer {
+	return l.layers
+}
+
+// Source returns the name of the layer which supplies the value for key.
+// If no layer has key set, found is false.
+func (l *Layered) Source(key string) (name string, found bool) {
+	if layer := l.layerFor(key); layer != nil {
+		return layer.Name, true
+	}
+	return "", false
+}
+
+func (l *Layered) layerFor(key string) *Layer {
+	for i := range l.layers {
+		if l.layers[i].Conf.IsSet(key) {
+			return &l.layers[i]
+		}
+	}
+	return nil
+}
+
+// InitDefaults calls InitDefaults for every layer.
+func (l *Layered) InitDefaults() {
+	for _, layer := range l.layers {
+		layer.Conf.InitDefaults()
+	}
+}
+
+// IsSet is a predicate wether any of the layers has a value for key.
+func (l *Layered) IsSet(key string) bool {
+	return l.layerFor(key) != nil
+}
+
+// GetString returns a configuration property as a string.
+func (l *Layered) GetString(key string) string {
+	if layer := l.layerFor(key); layer != nil {
+		return layer.Conf.GetString(key)
+	}
+	return ""
+}
+
+// GetInt returns a configuration property as an integer.
+func (l *Layered) GetInt(key string) int {
+	if layer := l.layerFor(key); layer != nil {
+		return layer.Conf.GetInt(key)
+	}
+	return 0
+}
+
+// GetBool returns a configuration property as a boolean value.
+func (l *Layered) GetBool(key string) bool {
+	if layer := l.layerFor(key); layer != nil {
+		return layer.Conf.GetBool(key)
+	}
+	return false
+}
+
+// GetFloat64 returns a configuration property as a float.
+func (l *Layered) GetFloat64(key string) float64 {
+	if layer := l.layerFor(key); layer != nil {
+		return Typed(layer.Conf).GetFloat64(key)
+	}
+	return 0
+}
+
+// GetDuration returns a configuration property as a time.Duration.
+func (l *Layered) GetDuration(key string) time.Duration {
+	if layer := l.layerFor(key); layer != nil {
+		return Typed(layer.Conf).GetDuration(key)
+	}
+	return 0
+}
+
+// GetTime returns a configuration property as a time.Time.
+func (l *Layered) GetTime(key string) time.Time {
+	if layer := l.layerFor(key); layer != nil {
+		return Typed(layer.Conf).GetTime(key)
+	}
+	return time.Time{}
+}
+
+// GetStringSlice returns a configuration property as a list of strings.
+func (l *Layered) GetStringSlice(key string) []string {
+	if layer := l.layerFor(key); layer != nil {
+		return Typed(layer.Conf).GetStringSlice(key)
+	}
+	return nil
+}
+
+// GetStringMap returns a configuration property as a dictionary.
+// Dictionaries of all layers are merged, with entries of
+// layers of higher precedence overriding those of lower precedence.
+func (l *Layered) GetStringMap(key string) map[string]any {
+	var m map[string]any
+	for _, layer := range l.layers {
+		m = mergeMaps(m, Typed(layer.Conf).GetStringMap(key))
+	}
+	return m
+}
+
+// LookupString returns a configuration property as a string, or an error
+// if the key is not set or the value cannot be converted.
+func (l *Layered) LookupString(key string) (string, error) {
+	return Strict(l.confFor(key)).LookupString(key)
+}
+
+// LookupInt returns a configuration property as an integer, or an error
+// if the key is not set or the value cannot be converted.
+func (l *Layered) LookupInt(key string) (int, error) {
+	return Strict(l.confFor(key)).LookupInt(key)
+}
+
+// LookupBool returns a configuration property as a boolean value, or an error
+// if the key is not set or the value cannot be converted.
+func (l *Layered) LookupBool(key string) (bool, error) {
+	return Strict(l.confFor(key)).LookupBool(key)
+}
+
+// LookupFloat64 returns a configuration property as a float, or an error
+// if the key is not set or the value cannot be converted.
+func (l *Layered) LookupFloat64(key string) (float64, error) {
+	return Strict(l.confFor(key)).LookupFloat64(key)
+}
+
+// LookupDuration returns a configuration property as a time.Duration, or an error
+// if the key is not set or the value cannot be converted.
+func (l *Layered) LookupDuration(key string) (time.Duration, error) {
+	return Strict(l.confFor(key)).LookupDuration(key)
+}
+
+// LookupTime returns a configuration property as a time.Time, or an error
+// if the key is not set or the value cannot be converted.
+func (l *Layered) LookupTime(key string) (time.Time, error) {
+	return Strict(l.confFor(key)).LookupTime(key)
+}
+
+// LookupStringSlice returns a configuration property as a list of strings, or an error
+// if the key is not set or the value cannot be converted.
+func (l *Layered) LookupStringSlice(key string) ([]string, error) {
+	return Strict(l.confFor(key)).LookupStringSlice(key)
+}
+
+// LookupStringMap returns a configuration property as a dictionary, or an error
+// if the key is not set or the value cannot be converted.
+// Dictionaries are merged across layers as with GetStringMap.
+func (l *Layered) LookupStringMap(key string) (map[string]any, error) {
+	var m map[string]any
+	for _, layer := range l.layers {
+		lm, err := Strict(layer.Conf).LookupStringMap(key)
+		if errors.Is(err, ErrKeyNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		m = mergeMaps(m, lm)
+	}
+	if m == nil {
+		return ConvertValue(key, nil, false, ToStringMap)
+	}
+	return m, nil
+}
+
+// confFor returns the configuration of the layer supplying key, or an empty
+// configuration if no layer has key set.
+func (l *Layered) confFor(key string) Configuration {
+	if layer := l.layerFor(key); layer != nil {
+		return layer.Conf
+	}
+	return emptyConf{}
+}
+
+// Origin returns the origin of the value for key, as reported by the layer
+// supplying it. Layers not tracking provenance report an origin of kind
+// SourceUnknown.
+func (l *Layered) Origin(key string) (Origin, bool) {
+	if layer := l.layerFor(key); layer != nil {
+		return originOf(layer.Conf, key), true
+	}
+	return Origin{}, false
+}
+
+// Origins returns the origins of all keys known to layers tracking provenance.
+// For keys known to more than one layer, the layer of highest precedence wins.
+func (l *Layered) Origins() map[string]Origin {
+	origins := make(map[string]Origin)
+	for i := len(l.layers) - 1; i >= 0; i-- {
+		if pc, ok := l.layers[i].Conf.(ProvenanceConfiguration); ok {
+			for key, o := range pc.Origins() {
+				origins[key] = o
+			}
+		}
+	}
+	return origins
+}
+
+// IsSecret is a predicate wether any of the layers considers the value of key
+// secret (see schuko.IsSecret).
+func (l *Layered) IsSecret(key string) bool {
+	for _, layer := range l.layers {
+		if IsSecret(layer.Conf, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns the keys set in any of the layers implementing
+// EnumerableConfiguration, restricted to prefix and in sorted order.
+func (l *Layered) Keys(prefix string) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, layer := range l.layers {
+		if ec, ok := layer.Conf.(EnumerableConfiguration); ok {
+			for _, key := range ec.Keys(prefix) {
+				if !seen[key] {
+					seen[key] = true
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// AllSettings returns the settings of all layers implementing
+// EnumerableConfiguration, merged as with GetStringMap.
+func (l *Layered) AllSettings() map[string]any {
+	m := make(map[string]any)
+	for _, layer := range l.layers {
+		if ec, ok := layer.Conf.(EnumerableConfiguration); ok {
+			m = mergeMaps(m, ec.AllSettings())
+		}
+	}
+	return m
+}
+
+var _ TypedConfiguration = &Layered{}
+var _ StrictConfiguration = &Layered{}
+var _ ProvenanceConfiguration = &Layered{}
+var _ EnumerableConfiguration = &Layered{}
+var _ SecretConfiguration = &Layered{}
+
+// mergeMaps merges dictionary low into high, adding entries not present in high.
+// Nested dictionaries are merged recursively. If high is nil, a new map is created.
+// low is not modified.
+func mergeMaps(high, low map[string]any) map[string]any {
+	if high == nil && low != nil {
+		high = make(map[string]any, len(low))
+	}
+	for k, v := range low {
+		hv, ok := high[k]
+		if !ok {
+			high[k] = v
+			continue
+		}
+		hm, hIsMap := hv.(map[string]any)
+		lm, lIsMap := v.(map[string]any)
+		if hIsMap && lIsMap {
+			high[k] = mergeMaps(copyMap(hm), lm)
+		}
+	}
+	return high
+}
+
+func copyMap(m map[string]any) map[string]any {
+	c := make(map[string]any, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// emptyConf is a configuration without any keys set.
+type emptyConf struct{}
+
+func (emptyConf) InitDefaults()           {}
+func (emptyConf) IsSet(string) bool       { return false }
+func (emptyConf) GetString(string) string { return "" }
+func (emptyConf) GetInt(string) int       { return 0 }
+func (emptyConf) GetBool(string) bool     { return false }
@@ -0,0 +1,39 @@
+package schuko_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/testconfig"
+)
+
+func TestLayeredPrecedence(t *testing.T) {
+	flags := testconfig.Conf{"server.port": "9090"}
+	file := testconfig.Conf{"server.port": "8080", "db.host": "db.local", "db.port": 5432}
+	defaults := testconfig.Conf{"db.host": "localhost", "db.user": "admin", "tracing.adapter": "go"}
+	conf := schuko.NewLayered(
+		schuko.Layer{Name: "flags", Conf: flags},
+		schuko.Layer{Name: "file", Conf: file},
+		schuko.Layer{Name: "defaults", Conf: defaults},
+	)
+	if p := conf.GetInt("server.port"); p != 9090 {
+		t.Errorf("expected flags to override file, got port %d", p)
+	}
+	if src, _ := conf.Source("server.port"); src != "flags" {
+		t.Errorf("expected server.port from flags, got %q", src)
+	}
+	if src, _ := conf.Source("tracing.adapter"); src != "defaults" {
+		t.Errorf("expected tracing.adapter from defaults, got %q", src)
+	}
+	if _, found := conf.Source("nope"); found || conf.IsSet("nope") {
+		t.Errorf("expected key nope to be unset in all layers")
+	}
+	m := conf.GetStringMap("db")
+	if m["host"] != "db.local" || m["user"] != "admin" || m["port"] != 5432 {
+		t.Errorf("expected db maps to be merged, got %v", m)
+	}
+	if _, err := conf.LookupInt("nope"); !errors.Is(err, schuko.ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+}
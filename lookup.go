@@ -0,0 +1,205 @@
+package schuko
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors returned by strict lookups of configuration values. Clients test for them
+// with errors.Is.
+var (
+	ErrKeyNotFound  = errors.New("configuration key not found")
+	ErrInvalidValue = errors.New("configuration value has invalid format")
+)
+
+// LookupError is the error type returned by strict lookups of configuration values.
+// It wraps either ErrKeyNotFound or ErrInvalidValue, and—for the latter—the
+// underlying conversion error.
+type LookupError struct {
+	Key   string // configuration key
+	Type  string // requested type of the value, e.g. "int"
+	Value any    // raw value found for Key, if any
+	Err   error  // ErrKeyNotFound or ErrInvalidValue
+	Cause error  // conversion error, if any
+}
+
+func (e *LookupError) Error() string {
+	if errors.Is(e.Err, ErrKeyNotFound) {
+		return fmt.Sprintf("config %q: %s", e.Key, e.Err.Error())
+	}
+	if e.Cause != nil {
+		return fmt.Sprintf("config %q: %s for type %s: %s", e.Key, e.Err.Error(), e.Type, e.Cause.Error())
+	}
+	return fmt.Sprintf("config %q: %s for type %s", e.Key, e.Err.Error(), e.Type)
+}
+
+// Unwrap makes LookupError usable with errors.Is and errors.As.
+func (e *LookupError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.Cause}
+}
+
+// StrictConfiguration is an optional extension of Configuration. In contrast to
+// the getters of Configuration, which silently return zero values, lookups
+// distinguish between a missing key (ErrKeyNotFound) and a value which cannot
+// be converted to the requested type (ErrInvalidValue).
+type StrictConfiguration interface {
+	Configuration
+	LookupString(key string) (string, error)
+	LookupInt(key string) (int, error)
+	LookupBool(key string) (bool, error)
+	LookupFloat64(key string) (float64, error)
+	LookupDuration(key string) (time.Duration, error)
+	LookupTime(key string) (time.Time, error)
+	LookupStringSlice(key string) ([]string, error)
+	LookupStringMap(key string) (map[string]any, error)
+}
+
+// Strict returns a StrictConfiguration for conf. If conf implements
+// StrictConfiguration, it is returned unchanged. Otherwise lookups will
+// use conf.IsSet to check for the existence of a key and convert the string
+// representation of a value, as returned by conf.GetString.
+func Strict(conf Configuration) StrictConfiguration {
+	if sc, ok := conf.(StrictConfiguration); ok {
+		return sc
+	}
+	return strictWrapper{conf}
+}
+
+// ConvertValue is a helper for configuration adapters implementing
+// StrictConfiguration. It converts a raw value v found for key, using
+// conversion function conv (e.g., ToInt), and wraps errors in a LookupError.
+// If found is false, ErrKeyNotFound is reported.
+func ConvertValue[T any](key string, v any, found bool, conv func(any) (T, error)) (T, error) {
+	var zero T
+	typename := fmt.Sprintf("%T", zero)
+	if !found {
+		return zero, &LookupError{Key: key, Type: typename, Err: ErrKeyNotFound}
+	}
+	x, err := conv(v)
+	if err != nil {
+		return zero, &LookupError{Key: key, Type: typename, Value: v, Err: ErrInvalidValue, Cause: err}
+	}
+	return x, nil
+}
+
+type strictWrapper struct {
+	Configuration
+}
+
+func (w strictWrapper) raw(key string) (any, bool) {
+	if !w.IsSet(key) {
+		return nil, false
+	}
+	return w.GetString(key), true
+}
+
+func (w strictWrapper) LookupString(key string) (string, error) {
+	v, found := w.raw(key)
+	return ConvertValue(key, v, found, ToString)
+}
+
+func (w strictWrapper) LookupInt(key string) (int, error) {
+	v, found := w.raw(key)
+	return ConvertValue(key, v, found, ToInt)
+}
+
+func (w strictWrapper) LookupBool(key string) (bool, error) {
+	v, found := w.raw(key)
+	return ConvertValue(key, v, found, ToBool)
+}
+
+func (w strictWrapper) LookupFloat64(key string) (float64, error) {
+	v, found := w.raw(key)
+	return ConvertValue(key, v, found, ToFloat64)
+}
+
+func (w strictWrapper) LookupDuration(key string) (time.Duration, error) {
+	v, found := w.raw(key)
+	return ConvertValue(key, v, found, ToDuration)
+}
+
+func (w strictWrapper) LookupTime(key string) (time.Time, error) {
+	v, found := w.raw(key)
+	return ConvertValue(key, v, found, ToTime)
+}
+
+func (w strictWrapper) LookupStringSlice(key string) ([]string, error) {
+	v, found := w.raw(key)
+	return ConvertValue(key, v, found, ToStringSlice)
+}
+
+func (w strictWrapper) LookupStringMap(key string) (map[string]any, error) {
+	v, found := w.raw(key)
+	return ConvertValue(key, v, found, ToStringMap)
+}
+
+// --- Conversion rules for base types ---------------------------------------
+
+// ToString converts a configuration value to a string. Scalar values are
+// formatted with fmt's "%v" verb.
+func ToString(v any) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return x, nil
+	case nil:
+		return "", errors.New("cannot convert nil to string")
+	case map[string]any, []any:
+		return "", fmt.Errorf("cannot convert %T to string", v)
+	}
+	return fmt.Sprintf("%v", v), nil
+}
+
+// ToInt converts a configuration value to an integer. It accepts all Go integer
+// types, floats without fractional part, and strings in a format understood
+// by strconv.Atoi.
+func ToInt(v any) (int, error) {
+	switch x := v.(type) {
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(x))
+		if err != nil {
+			return 0, fmt.Errorf("cannot convert %q to int: %w", x, err)
+		}
+		return n, nil
+	case float64:
+		if x != math.Trunc(x) {
+			return 0, fmt.Errorf("cannot convert %v to int: has fractional part", x)
+		}
+		return int(x), nil
+	case float32:
+		if float64(x) != math.Trunc(float64(x)) {
+			return 0, fmt.Errorf("cannot convert %v to int: has fractional part", x)
+		}
+		return int(x), nil
+	}
+	if n, ok := toInt64(v); ok {
+		return int(n), nil
+	}
+	return 0, fmt.Errorf("cannot convert %T to int", v)
+}
+
+// ToBool converts a configuration value to a boolean value. Strings are
+// interpreted by strconv.ParseBool, i.e. "1", "t", "true", "0", "f", "false"
+// and variants thereof are accepted.
+func ToBool(v any) (bool, error) {
+	switch x := v.(type) {
+	case bool:
+		return x, nil
+	case string:
+		b, err := strconv.ParseBool(strings.TrimSpace(x))
+		if err != nil {
+			return false, fmt.Errorf("cannot convert %q to bool: %w", x, err)
+		}
+		return b, nil
+	}
+	if n, ok := toInt64(v); ok && (n == 0 || n == 1) {
+		return n == 1, nil
+	}
+	return false, fmt.Errorf("cannot convert %v to bool", v)
+}
@@ -0,0 +1,113 @@
+package schuko_test
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/envadapter"
+	"github.com/npillmayer/schuko/schukonf/flagadapter"
+	"github.com/npillmayer/schuko/schukonf/koanfadapter"
+	"github.com/npillmayer/schuko/schukonf/testadapter"
+	"github.com/npillmayer/schuko/schukonf/testconfig"
+	"github.com/npillmayer/schuko/schukonf/viperadapter"
+)
+
+func TestLookupMissingVersusInvalid(t *testing.T) {
+	kconf := koanfadapter.New(nil, "", nil)
+	kconf.Set("port", "abc")
+	for _, conf := range []schuko.Configuration{
+		testconfig.Conf{"port": "abc"},
+		kconf,
+	} {
+		sc := schuko.Strict(conf)
+		_, err := sc.LookupInt("prot")
+		if !errors.Is(err, schuko.ErrKeyNotFound) {
+			t.Errorf("%T: expected ErrKeyNotFound for typo, got %v", conf, err)
+		}
+		_, err = sc.LookupInt("port")
+		if !errors.Is(err, schuko.ErrInvalidValue) {
+			t.Errorf("%T: expected ErrInvalidValue for \"abc\", got %v", conf, err)
+		}
+		var lerr *schuko.LookupError
+		if !errors.As(err, &lerr) || lerr.Key != "port" || lerr.Type != "int" {
+			t.Errorf("%T: expected LookupError for key port, got %#v", conf, err)
+		}
+	}
+}
+
+func TestLookupFallback(t *testing.T) {
+	type plain struct{ schuko.Configuration } // hides the strict interface
+	sc := schuko.Strict(plain{testconfig.Conf{"debug": "yes", "n": "42"}})
+	if n, err := sc.LookupInt("n"); err != nil || n != 42 {
+		t.Errorf("expected n = 42, got %d (%v)", n, err)
+	}
+	if _, err := sc.LookupBool("debug"); !errors.Is(err, schuko.ErrInvalidValue) {
+		t.Errorf("expected ErrInvalidValue for \"yes\", got %v", err)
+	}
+}
+
+func TestStringMapAcrossAdapters(t *testing.T) {
+	values := map[string]string{"db.host": "localhost", "db.pool.size": "10"}
+	kconf := koanfadapter.New(nil, "", nil)
+	vconf := viperadapter.New("test")
+	tconf := testadapter.New()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fconf := flagadapter.New(fs)
+	var args []string
+	for key, v := range values {
+		kconf.Set(key, v)
+		vconf.Set(key, v)
+		tconf.Set(key, v)
+		if err := fconf.Register(flagadapter.Flag{Key: key, Default: ""}); err != nil {
+			t.Fatal(err)
+		}
+		args = append(args, "--"+flagadapter.FlagName(key), v)
+	}
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MAPTEST_DB_HOST", "localhost")
+	t.Setenv("MAPTEST_DB_POOL_SIZE", "10")
+	want := map[string]any{"host": "localhost", "pool": map[string]any{"size": "10"}}
+	for name, conf := range map[string]schuko.Configuration{
+		"testconfig":  testconfig.Conf{"db.host": "localhost", "db.pool.size": "10"},
+		"testadapter": tconf,
+		"koanf":       kconf,
+		"viper":       vconf,
+		"env":         envadapter.New("MAPTEST"),
+		"flag":        fconf,
+	} {
+		if m := schuko.Typed(conf).GetStringMap("db"); !reflect.DeepEqual(m, want) {
+			t.Errorf("%s: expected GetStringMap to return %v, got %v", name, want, m)
+		}
+		if m, err := schuko.Strict(conf).LookupStringMap("db"); err != nil || !reflect.DeepEqual(m, want) {
+			t.Errorf("%s: expected LookupStringMap to return %v, got %v (%v)", name, want, m, err)
+		}
+	}
+}
+
+func TestTestDoublesAgree(t *testing.T) {
+	values := map[string]string{"verbose": "1", "quiet": "F", "db/host": "localhost", "db/pool.size": "10"}
+	tconf := testadapter.New()
+	cconf := testconfig.Conf{}
+	for key, v := range values {
+		tconf.Set(key, v)
+		cconf[key] = v
+	}
+	want := map[string]any{"host": "localhost", "pool": map[string]any{"size": "10"}}
+	for name, conf := range map[string]schuko.Configuration{"testconfig": cconf, "testadapter": tconf} {
+		for key, expected := range map[string]bool{"verbose": true, "quiet": false} {
+			b, err := schuko.Strict(conf).LookupBool(key)
+			if conf.GetBool(key) != expected || b != expected || err != nil {
+				t.Errorf("%s: expected %s = %v from GetBool and LookupBool, got %v and %v (%v)",
+					name, key, expected, conf.GetBool(key), b, err)
+			}
+		}
+		if m := schuko.Typed(conf).GetStringMap("db"); !reflect.DeepEqual(m, want) {
+			t.Errorf("%s: expected GetStringMap to return %v, got %v", name, want, m)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package schuko
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PersistentConfiguration is an optional extension of Configuration for
+// configurations which are able to write values changed at runtime back to
+// disk, e.g. user preferences of desktop applications.
+type PersistentConfiguration interface {
+	Configuration
+	Save() (path string, err error) // write modified values to the configuration file
+}
+
+// ErrNoConfigFile is reported if no file can be determined to save a
+// configuration to.
+var ErrNoConfigFile = errors.New("cannot determine configuration file")
+
+// SaveTarget returns the path of the file to save the configuration of an
+// application to. This is the main configuration file found by LocateConfig
+// with the highest precedence, not considering drop-in files. If no file
+// exists, the path of a new file “config.<suffix>” in the application's
+// directory below os.UserConfigDir is returned, with suffix being the first
+// entry of suffixes.
+//
+// SaveTarget uses DefaultSearchPath; see SearchPath.SaveTarget for others.
+func SaveTarget(appTag string, suffixes []string) (string, error) {
+	return DefaultSearchPath().SaveTarget(appTag, suffixes)
+}
+
+// SaveTarget returns the path of the file to save the configuration of an
+// application to, as SaveTarget does, but considers the files located by sp.
+// Missing files (see ConfigFile) are not considered.
+func (sp *SearchPath) SaveTarget(appTag string, suffixes []string) (string, error) {
+	if appTag == "" || len(suffixes) == 0 {
+		return "", ErrNoConfigFile
+	}
+	files := sp.Locate(appTag, "", suffixes)
+	for i := len(files) - 1; i >= 0; i-- {
+		if !files[i].Missing && filepath.Base(filepath.Dir(files[i].Path)) != DropInDir {
+			return files[i].Path, nil
+		}
+	}
+	confdir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.Join(ErrNoConfigFile, err)
+	}
+	return filepath.Join(confdir, strings.ToLower(appTag), "config."+suffixes[0]), nil
+}
+
+// WriteFileAtomic writes data to a file, creating directories as needed.
+// Data is written to a temporary file in the same directory first, which is
+// then renamed to path. Readers will therefore see either the old or the new
+// content, but never a partially written file. If the file exists, its
+// permissions are kept, otherwise perm is used.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if fi, err := os.Stat(path); err == nil {
+		perm = fi.Mode().Perm()
+	}
+	base := filepath.Base(path)
+	tmp, err := os.CreateTemp(dir, "."+base+"-*"+filepath.Ext(base))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after successful rename
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,53 @@
+package schuko_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a", "b", "config.nt")
+	if err := schuko.WriteFileAtomic(path, []byte("name: one\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := schuko.WriteFileAtomic(path, []byte("name: two\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil || string(b) != "name: two\n" {
+		t.Errorf("expected file to be replaced, got %q (%v)", b, err)
+	}
+	if fi, _ := os.Stat(path); fi.Mode().Perm() != 0o640 {
+		t.Errorf("expected permissions of existing file to be kept, got %v", fi.Mode())
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("expected no temporary files to be left, got %v", entries)
+	}
+}
+
+func TestSaveTarget(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	dir := filepath.Join(home, ".config", "savetest")
+	path, err := schuko.SaveTarget("SaveTest", []string{"nt", "yaml"})
+	if err != nil || path != filepath.Join(dir, "config.nt") {
+		t.Errorf("expected new file in user config dir, got %q (%v)", path, err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, schuko.DropInDir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"savetest.yaml", filepath.Join(schuko.DropInDir, "10-x.nt")} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path, err = schuko.SaveTarget("savetest", []string{"nt", "yaml"})
+	if err != nil || path != filepath.Join(dir, "savetest.yaml") {
+		t.Errorf("expected located main file, got %q (%v)", path, err)
+	}
+}
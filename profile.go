@@ -0,0 +1,247 @@
+package schuko
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Names of the profiles known by default. Clients may add profiles with
+// RegisterProfile.
+const (
+	ProfileDevelopment = "dev"
+	ProfileTest        = "test"
+	ProfileProduction  = "prod"
+)
+
+// ProfileSection is the key of the configuration section holding settings
+// for profiles, e.g. "profiles.prod.tracing.adapter".
+const ProfileSection = "profiles"
+
+var profiles = map[string]func(appTag string) map[string]any{
+	ProfileDevelopment: func(string) map[string]any {
+		return map[string]any{
+			"tracing.adapter":     "go",
+			"tracing.destination": "Stderr",
+			"tracelevel.root":     "Debug",
+		}
+	},
+	ProfileTest: func(string) map[string]any {
+		return map[string]any{
+			"tracing.adapter": "test",
+			"tracelevel.root": "Debug",
+		}
+	},
+	ProfileProduction: func(appTag string) map[string]any {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			dir = os.TempDir()
+		}
+		tag := strings.ToLower(appTag)
+		return map[string]any{
+			"tracing.adapter":     "slogjson",
+			"tracing.destination": "file://" + filepath.ToSlash(filepath.Join(dir, tag, tag+".log")),
+			"tracelevel.root":     "Info",
+		}
+	},
+}
+var profileMutex = &sync.RWMutex{} // guards profiles and selectedProfile
+var selectedProfile string
+
+// RegisterProfile is an extension point for clients who want to use profiles
+// other than the built-in ones, or change the defaults of a built-in profile.
+// defaults provides default values for a profile, given the application tag.
+//
+// The built-in profiles provide these tracing defaults (trace levels are given
+// for prefix key "tracelevel", see trace2go.ConfigureRoot):
+//
+//	dev:   tracing.adapter = go, tracing.destination = Stderr, tracelevel.root = Debug
+//	test:  tracing.adapter = test, tracelevel.root = Debug
+//	prod:  tracing.adapter = slogjson, tracelevel.root = Info,
+//	       tracing.destination = file://<user cache dir>/<tag>/<tag>.log
+//
+// Adapters have to be registered with these keys by the client, e.g.
+//
+//	tracing.RegisterTraceAdapter("slogjson", goslogadapter.GetJSONAdapter(), false)
+func RegisterProfile(name string, defaults func(appTag string) map[string]any) {
+	profileMutex.Lock()
+	defer profileMutex.Unlock()
+	profiles[name] = defaults
+}
+
+// Profiles returns the names of all registered profiles, in sorted order.
+func Profiles() []string {
+	profileMutex.RLock()
+	defer profileMutex.RUnlock()
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SelectProfile sets the active profile explicitly, taking precedence over
+// environment variables. An empty name reverts to selection by environment
+// variables.
+func SelectProfile(name string) {
+	profileMutex.Lock()
+	defer profileMutex.Unlock()
+	selectedProfile = name
+}
+
+// ProfileEnvName returns the name of the environment variable selecting the
+// profile for an application, e.g. MYAPP_PROFILE for tag "myapp".
+func ProfileEnvName(appTag string) string {
+	return strings.ToUpper(appTag) + "_PROFILE"
+}
+
+// ActiveProfile returns the name of the active profile for an application.
+// A profile set by SelectProfile takes precedence, otherwise the profile is
+// taken from environment variable <TAG>_PROFILE (see ProfileEnvName).
+// If no profile is selected, the empty string is returned.
+func ActiveProfile(appTag string) string {
+	profileMutex.RLock()
+	name := selectedProfile
+	profileMutex.RUnlock()
+	if name != "" {
+		return name
+	}
+	return strings.ToLower(os.Getenv(ProfileEnvName(appTag)))
+}
+
+// ProfileDefaults returns the default values of a profile for an application,
+// as flat keys. For unknown profiles, an empty map is returned.
+func ProfileDefaults(appTag, profile string) map[string]any {
+	profileMutex.RLock()
+	defaults := profiles[profile]
+	profileMutex.RUnlock()
+	if defaults == nil {
+		return map[string]any{}
+	}
+	return defaults(appTag)
+}
+
+// ProfileFiles searches for configuration files specific to a profile, using
+// LocateConfig. Files have to be named <tag>.<profile>.<suffix>, e.g.
+// “myapp.prod.nt”. See SearchPath.ProfileFiles for other locations.
+func ProfileFiles(appTag, profile string, suffixes []string) []string {
+	return Paths(DefaultSearchPath().ProfileFiles(appTag, profile, suffixes))
+}
+
+// ProfileFiles searches for configuration files specific to a profile, as
+// ProfileFiles does, but at the locations of sp. Files given explicitly, e.g.
+// by EnvOverrideRule, are not profile-specific and therefore not returned,
+// nor are missing files.
+func (sp *SearchPath) ProfileFiles(appTag, profile string, suffixes []string) []ConfigFile {
+	if profile == "" {
+		return nil
+	}
+	var files []ConfigFile
+	for _, f := range sp.Locate(appTag, strings.ToLower(appTag)+"."+profile+".*", suffixes) {
+		if !f.Missing && f.Path != f.Dir {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// WithProfile overlays a base configuration with the settings of a profile.
+// Settings are taken, in order of precedence, from
+//
+//  1. the overlays, e.g. configurations loaded from profile files (see ProfileFiles)
+//  2. the profile section of base, i.e. keys "profiles.<profile>.*"
+//  3. base itself
+//  4. the defaults of the profile (see RegisterProfile)
+//
+// Defaults of a profile replace values of base which originate from built-in
+// defaults of base (see ProvenanceConfiguration), but not values from files,
+// environment variables, etc. If profile is empty, no profile-specific settings
+// are applied.
+func WithProfile(base Configuration, appTag, profile string, overlays ...Layer) *Layered {
+	if profile == "" {
+		layers := append(slices.Clone(overlays), Layer{Name: "base", Conf: base})
+		return NewLayered(layers...)
+	}
+	pd := &profileDefaults{name: profile, values: ProfileDefaults(appTag, profile), base: base}
+	layers := append(slices.Clone(overlays),
+		Layer{Name: "profile " + profile, Conf: Sub(base, ProfileSection+"."+profile)},
+		Layer{Name: "profile defaults", Conf: pd},
+		Layer{Name: "base", Conf: base},
+	)
+	return NewLayered(layers...)
+}
+
+// profileDefaults holds the defaults of a profile. Keys are considered set
+// only if base does not have a value of higher precedence than built-in
+// defaults.
+type profileDefaults struct {
+	name   string
+	values map[string]any
+	base   Configuration
+}
+
+func (p *profileDefaults) InitDefaults() {}
+
+func (p *profileDefaults) IsSet(key string) bool {
+	if _, ok := p.values[key]; !ok {
+		return false
+	}
+	if !p.base.IsSet(key) {
+		return true
+	}
+	return originOf(p.base, key).Kind == SourceDefault
+}
+
+func (p *profileDefaults) GetString(key string) string {
+	s, _ := ToString(p.values[key])
+	return s
+}
+
+func (p *profileDefaults) GetInt(key string) int {
+	n, _ := ToInt(p.values[key])
+	return n
+}
+
+func (p *profileDefaults) GetBool(key string) bool {
+	b, _ := ToBool(p.values[key])
+	return b
+}
+
+func (p *profileDefaults) Origin(key string) (Origin, bool) {
+	if !p.IsSet(key) {
+		return Origin{}, false
+	}
+	return Origin{Kind: SourceDefault, Name: "profile " + p.name}, true
+}
+
+func (p *profileDefaults) Origins() map[string]Origin {
+	origins := make(map[string]Origin)
+	for key := range p.values {
+		if o, found := p.Origin(key); found {
+			origins[key] = o
+		}
+	}
+	return origins
+}
+
+func (p *profileDefaults) Keys(prefix string) []string {
+	var keys []string
+	for key := range p.values {
+		if p.IsSet(key) {
+			keys = append(keys, key)
+		}
+	}
+	return FilterKeys(keys, prefix)
+}
+
+func (p *profileDefaults) AllSettings() map[string]any {
+	flat := make(map[string]any)
+	for _, key := range p.Keys("") {
+		flat[key] = p.values[key]
+	}
+	return Unflatten(flat)
+}
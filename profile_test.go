@@ -0,0 +1,88 @@
+package schuko_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/testconfig"
+)
+
+func TestActiveProfile(t *testing.T) {
+	t.Setenv("PROFTEST_PROFILE", "Prod")
+	if p := schuko.ActiveProfile("proftest"); p != schuko.ProfileProduction {
+		t.Errorf("expected profile from environment, got %q", p)
+	}
+	schuko.SelectProfile(schuko.ProfileDevelopment)
+	defer schuko.SelectProfile("")
+	if p := schuko.ActiveProfile("proftest"); p != schuko.ProfileDevelopment {
+		t.Errorf("expected explicitly selected profile, got %q", p)
+	}
+}
+
+func TestWithProfile(t *testing.T) {
+	base := testconfig.Conf{
+		"name":                       "app",
+		"tracing.destination":        "Stdout",
+		"profiles.prod.name":         "app-prod",
+		"profiles.dev.name":          "app-dev",
+		"profiles.prod.db.pool.size": 20,
+	}
+	overlay := testconfig.Conf{"db.pool.size": 50}
+	conf := schuko.WithProfile(base, "proftest", schuko.ProfileProduction,
+		schuko.Layer{Name: "proftest.prod.nt", Conf: overlay})
+	if n := conf.GetString("name"); n != "app-prod" {
+		t.Errorf("expected name from profile section, got %q", n)
+	}
+	if n := conf.GetInt("db.pool.size"); n != 50 {
+		t.Errorf("expected pool size from overlay, got %d", n)
+	}
+	if a := conf.GetString("tracing.adapter"); a != "slogjson" {
+		t.Errorf("expected tracing adapter from profile defaults, got %q", a)
+	}
+	if l := conf.GetString("tracelevel.root"); l != "Info" {
+		t.Errorf("expected root trace level from profile defaults, got %q", l)
+	}
+	if d := conf.GetString("tracing.destination"); d != "Stdout" {
+		t.Errorf("expected destination of base to take precedence, got %q", d)
+	}
+	if exp := schuko.Explain(conf, "tracing.adapter"); exp.Layer != "profile defaults" {
+		t.Errorf("expected tracing.adapter to be explained by profile defaults, got %v", exp)
+	}
+	//
+	conf = schuko.WithProfile(base, "proftest", "")
+	if n := conf.GetString("name"); n != "app" {
+		t.Errorf("expected name from base without profile, got %q", n)
+	}
+}
+
+func TestProfileFiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	dir := filepath.Join(home, ".config", "proftest")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"config.nt", "proftest.prod.nt", "proftest.dev.nt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("a: b\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := schuko.ProfileFiles("proftest", "prod", []string{"nt"})
+	if len(files) != 1 || filepath.Base(files[0]) != "proftest.prod.nt" {
+		t.Errorf("expected profile file proftest.prod.nt, got %v", files)
+	}
+	files = schuko.LocateConfig("proftest", "", []string{"nt"})
+	if len(files) != 1 || filepath.Base(files[0]) != "config.nt" {
+		t.Errorf("expected profile files to be excluded from default files, got %v", files)
+	}
+	t.Setenv(schuko.ConfigEnvName("proftest"), filepath.Join(dir, "config.nt"))
+	sp := schuko.NewSearchPath(schuko.CollectAll(),
+		schuko.WithRules(schuko.EnvOverrideRule(), schuko.XDGConfigHomeRule()))
+	files = schuko.Paths(sp.ProfileFiles("proftest", "prod", []string{"nt"}))
+	if len(files) != 1 || filepath.Base(files[0]) != "proftest.prod.nt" {
+		t.Errorf("expected file given by environment not to be a profile file, got %v", files)
+	}
+}
@@ -0,0 +1,193 @@
+package schuko
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// SourceKind classifies the origin of a configuration value.
+type SourceKind int
+
+// Kinds of sources for configuration values.
+const (
+	SourceUnknown SourceKind = iota // origin not tracked
+	SourceDefault                   // built-in or programmatic default
+	SourceFile                      // configuration file
+	SourceEnv                       // environment variable
+	SourceFlag                      // command-line flag
+	SourceSet                       // set at runtime, e.g. by a call to Set
+)
+
+func (k SourceKind) String() string {
+	switch k {
+	case SourceDefault:
+		return "default"
+	case SourceFile:
+		return "file"
+	case SourceEnv:
+		return "env"
+	case SourceFlag:
+		return "flag"
+	case SourceSet:
+		return "set"
+	}
+	return "unknown"
+}
+
+// Origin describes where a configuration value came from.
+type Origin struct {
+	Kind SourceKind // kind of source
+	Name string     // file path, variable name or flag name; may be empty
+	Line int        // line within a configuration file, if known; 0 otherwise
+}
+
+func (o Origin) String() string {
+	switch {
+	case o.Name == "":
+		return o.Kind.String()
+	case o.Line > 0:
+		return fmt.Sprintf("%s %s:%d", o.Kind, o.Name, o.Line)
+	}
+	return o.Kind.String() + " " + o.Name
+}
+
+// ProvenanceConfiguration is an optional extension of Configuration. Adapters
+// implement it to record the origin of every configuration value they hold.
+type ProvenanceConfiguration interface {
+	Configuration
+	Origin(key string) (Origin, bool) // origin of the value for key, if set
+	Origins() map[string]Origin       // origins of all keys known to the adapter
+}
+
+// Explanation tells the value of a configuration key and where it came from.
+type Explanation struct {
+	Key      string   // configuration key
+	Value    string   // value as returned by GetString
+	Set      bool     // is the key set at all?
+	Origin   Origin   // origin of the value
+	Layer    string   // name of the layer supplying the value, for Layered configurations
+	Shadowed []Origin // origins of values overridden by layers of higher precedence
+}
+
+func (e Explanation) String() string {
+	if !e.Set {
+		return e.Key + " is not set"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s = %q (%s", e.Key, e.Value, e.Origin)
+	if e.Layer != "" {
+		fmt.Fprintf(&b, ", layer %s", e.Layer)
+	}
+	b.WriteString(")")
+	for _, o := range e.Shadowed {
+		fmt.Fprintf(&b, "; overrides %s", o)
+	}
+	return b.String()
+}
+
+// Explain returns the value of key in conf together with its origin.
+// If conf does not track provenance, the origin is of kind SourceUnknown.
+// For Layered configurations, the layer supplying the value and the origins
+// of values overridden by it are reported as well. Values of secret keys (see
+// IsSecret) are redacted.
+func Explain(conf Configuration, key string) Explanation {
+	e := Explanation{Key: key, Set: conf.IsSet(key)}
+	if !e.Set {
+		return e
+	}
+	e.Value = conf.GetString(key)
+	if IsSecret(conf, key) {
+		e.Value = Redacted
+	}
+	if l, ok := conf.(*Layered); ok {
+		supplied := false
+		for _, layer := range l.layers {
+			if !layer.Conf.IsSet(key) {
+				continue
+			}
+			o := originOf(layer.Conf, key)
+			if !supplied {
+				e.Origin, e.Layer, supplied = o, layer.Name, true
+				continue
+			}
+			e.Shadowed = append(e.Shadowed, o)
+		}
+		return e
+	}
+	e.Origin = originOf(conf, key)
+	return e
+}
+
+// ExplainAll explains every key for which conf knows an origin, sorted by key.
+// conf has to implement ProvenanceConfiguration, otherwise ExplainAll returns nil.
+func ExplainAll(conf Configuration) []Explanation {
+	pc, ok := conf.(ProvenanceConfiguration)
+	if !ok {
+		return nil
+	}
+	origins := pc.Origins()
+	keys := make([]string, 0, len(origins))
+	for key := range origins {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	exps := make([]Explanation, 0, len(keys))
+	for _, key := range keys {
+		if e := Explain(conf, key); e.Set {
+			exps = append(exps, e)
+		}
+	}
+	return exps
+}
+
+// WriteExplanations writes explanations as text, one line per key.
+func WriteExplanations(w io.Writer, exps []Explanation) error {
+	for _, e := range exps {
+		if _, err := fmt.Fprintln(w, e.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExplanationMap converts explanations to a dictionary, keyed by configuration
+// key. It is intended for serialization, e.g. to NestedText (see package
+// koanfadapter).
+func ExplanationMap(exps []Explanation) map[string]any {
+	m := make(map[string]any, len(exps))
+	for _, e := range exps {
+		entry := map[string]any{
+			"value":  e.Value,
+			"source": e.Origin.Kind.String(),
+		}
+		if e.Origin.Name != "" {
+			entry["name"] = e.Origin.Name
+		}
+		if e.Origin.Line > 0 {
+			entry["line"] = fmt.Sprintf("%d", e.Origin.Line)
+		}
+		if e.Layer != "" {
+			entry["layer"] = e.Layer
+		}
+		if len(e.Shadowed) > 0 {
+			shadowed := make([]any, len(e.Shadowed))
+			for i, o := range e.Shadowed {
+				shadowed[i] = o.String()
+			}
+			entry["overrides"] = shadowed
+		}
+		m[e.Key] = entry
+	}
+	return m
+}
+
+func originOf(conf Configuration, key string) Origin {
+	if pc, ok := conf.(ProvenanceConfiguration); ok {
+		if o, found := pc.Origin(key); found {
+			return o
+		}
+	}
+	return Origin{}
+}
@@ -0,0 +1,72 @@
+package schuko_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/envadapter"
+	"github.com/npillmayer/schuko/schukonf/testadapter"
+	"github.com/npillmayer/schuko/schukonf/testconfig"
+)
+
+func TestExplainLayered(t *testing.T) {
+	t.Setenv("PROVTEST_SERVER_PORT", "9090")
+	env := envadapter.New("PROVTEST")
+	defaults := testadapter.New()
+	defaults.InitDefaults()
+	defaults.Set("server.port", "8080")
+	conf := schuko.NewLayered(
+		schuko.Layer{Name: "env", Conf: env},
+		schuko.Layer{Name: "file", Conf: testconfig.Conf{"server.host": "localhost"}},
+		schuko.Layer{Name: "defaults", Conf: defaults},
+	)
+	e := schuko.Explain(conf, "server.port")
+	if e.Value != "9090" || e.Layer != "env" {
+		t.Errorf("expected server.port = 9090 from layer env, got %v", e)
+	}
+	if e.Origin.Kind != schuko.SourceEnv || e.Origin.Name != "PROVTEST_SERVER_PORT" {
+		t.Errorf("expected server.port to originate from env var, got %v", e.Origin)
+	}
+	if len(e.Shadowed) != 1 || e.Shadowed[0].Kind != schuko.SourceSet {
+		t.Errorf("expected value set in defaults to be shadowed, got %v", e.Shadowed)
+	}
+	if e := schuko.Explain(conf, "server.host"); e.Origin.Kind != schuko.SourceSet || e.Layer != "file" {
+		t.Errorf("expected server.host to be set in layer file, got %v", e)
+	}
+	if e := schuko.Explain(conf, "tracing.adapter"); e.Origin.Kind != schuko.SourceDefault {
+		t.Errorf("expected tracing.adapter to be a default, got %v", e)
+	}
+	if e := schuko.Explain(conf, "nope"); e.Set {
+		t.Errorf("expected nope to be unset, got %v", e)
+	}
+}
+
+func TestWriteExplanations(t *testing.T) {
+	conf := testadapter.New()
+	conf.InitDefaults()
+	conf.Set("db.host", "db.local")
+	var buf bytes.Buffer
+	if err := schuko.WriteExplanations(&buf, schuko.ExplainAll(conf)); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("explanations:\n%s", buf.String())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || lines[0] != `db.host = "db.local" (set)` {
+		t.Errorf("unexpected explanations %q", lines)
+	}
+}
+
+func TestExplainTestConfig(t *testing.T) {
+	conf := testconfig.Conf{"db.host": "localhost", "db.port": 5432}
+	if o, found := conf.Origin("db.host"); !found || o.Kind != schuko.SourceSet {
+		t.Errorf("expected db.host to be set, got %v", o)
+	}
+	if _, found := conf.Origin("db.user"); found {
+		t.Errorf("expected no origin for unset key")
+	}
+	if exps := schuko.ExplainAll(conf); len(exps) != 2 || exps[0].Key != "db.host" || exps[1].Key != "db.port" {
+		t.Errorf("expected explanations for db.host and db.port, got %v", exps)
+	}
+}
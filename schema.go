@@ -0,0 +1,456 @@
+package schuko
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownKey is reported for configuration keys not declared in a schema.
+var ErrUnknownKey = errors.New("configuration key not declared in schema")
+
+// ValueType is the type of a configuration value declared in a schema.
+type ValueType int
+
+// Types of configuration values. TypeAny accepts every value.
+const (
+	TypeAny ValueType = iota
+	TypeString
+	TypeInt
+	TypeBool
+	TypeFloat
+	TypeDuration
+	TypeTime
+	TypeStringSlice
+	TypeStringMap
+)
+
+func (t ValueType) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeInt:
+		return "int"
+	case TypeBool:
+		return "bool"
+	case TypeFloat:
+		return "float"
+	case TypeDuration:
+		return "duration"
+	case TypeTime:
+		return "time"
+	case TypeStringSlice:
+		return "list"
+	case TypeStringMap:
+		return "dict"
+	}
+	return "any"
+}
+
+// Rule declares a configuration key of a schema. Rules are created with Setting.
+type Rule struct {
+	Key         string // configuration key; a segment "*" is a wildcard (see Setting)
+	Type        ValueType
+	Description string // optional description, e.g. for documentation
+	required    bool
+	oneOf       func() []string
+	hasRange    bool
+	min, max    float64
+	pattern     *regexp.Regexp
+	secret      bool
+}
+
+// RuleOption is a type to add constraints to a rule.
+// Multiple options may be passed to `Setting(…)`.
+type RuleOption func(*Rule)
+
+// Setting declares a configuration key of type t. Wildcards are allowed for
+// keys: a segment "*" matches exactly one key segment, except for a trailing
+// "*", which matches one or more segments. Example:
+//
+//	schuko.Setting("tracelevel.*", schuko.TypeString, schuko.OneOf("Debug", "Info", "Error"))
+//
+// will match keys "tracelevel.root" and "tracelevel.myapp.db".
+func Setting(key string, t ValueType, opts ...RuleOption) *Rule {
+	r := &Rule{Key: key, Type: t}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Required marks a key as mandatory. Required is ignored for keys with wildcards.
+func Required() RuleOption {
+	return func(r *Rule) {
+		r.required = true
+	}
+}
+
+// OneOf restricts values to a set of allowed values. Values are compared
+// case-insensitively.
+func OneOf(values ...string) RuleOption {
+	return func(r *Rule) {
+		r.oneOf = func() []string { return values }
+	}
+}
+
+// OneOfFunc restricts values to a set of allowed values, which is determined
+// at validation time. This is useful for values referring to registries, e.g.
+// of tracing adapters.
+func OneOfFunc(values func() []string) RuleOption {
+	return func(r *Rule) {
+		r.oneOf = values
+	}
+}
+
+// Range restricts numeric values (TypeInt, TypeFloat) to [min…max].
+func Range(min, max float64) RuleOption {
+	return func(r *Rule) {
+		r.hasRange, r.min, r.max = true, min, max
+	}
+}
+
+// Matches restricts values to strings matching a regular expression.
+// It panics if pattern does not compile.
+func Matches(pattern string) RuleOption {
+	re := regexp.MustCompile(pattern)
+	return func(r *Rule) {
+		r.pattern = re
+	}
+}
+
+// Sensitive marks values of a key as secret (see Secrets). Secret values are
+// not shown in messages of violations.
+func Sensitive() RuleOption {
+	return func(r *Rule) {
+		r.secret = true
+	}
+}
+
+// IsSecret is a predicate wether a rule marks values as secret.
+func (r *Rule) IsSecret() bool {
+	return r.secret
+}
+
+// Description adds a human-readable description to a rule.
+func Description(text string) RuleOption {
+	return func(r *Rule) {
+		r.Description = text
+	}
+}
+
+// Schema is a set of rules for configuration keys. Configurations are checked
+// against a schema by Validate.
+type Schema struct {
+	rules []*Rule
+}
+
+// NewSchema creates a schema from a set of rules.
+func NewSchema(rules ...*Rule) *Schema {
+	return &Schema{rules: rules}
+}
+
+// Add adds rules to a schema.
+func (s *Schema) Add(rules ...*Rule) *Schema {
+	s.rules = append(s.rules, rules...)
+	return s
+}
+
+// Include adds all rules of another schema, e.g. of a library or of the
+// tracing keys (see tracing.Schema).
+func (s *Schema) Include(other *Schema) *Schema {
+	return s.Add(other.rules...)
+}
+
+// Rules returns the rules of the schema.
+func (s *Schema) Rules() []*Rule {
+	return s.rules
+}
+
+// IsSecret is a predicate wether a rule matching key marks it as secret
+// (see option Sensitive).
+func (s *Schema) IsSecret(key string) bool {
+	for _, r := range s.rules {
+		if r.secret && (r.Key == key || (r.isPattern() && r.matches(key))) {
+			return true
+		}
+	}
+	return false
+}
+
+// Violation describes a configuration key failing a rule of a schema, or a
+// key unknown to a schema.
+type Violation struct {
+	Key        string // configuration key
+	Err        error  // ErrKeyNotFound, ErrInvalidValue or ErrUnknownKey
+	Reason     string // description of the violation
+	Suggestion string // for unknown keys: a similar key declared in the schema, if any
+}
+
+func (v Violation) Error() string {
+	msg := fmt.Sprintf("config %q: %s", v.Key, v.Reason)
+	if v.Suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean %q?)", v.Suggestion)
+	}
+	return msg
+}
+
+// Unwrap makes Violation usable with errors.Is.
+func (v Violation) Unwrap() error {
+	return v.Err
+}
+
+// ValidationError is returned by Validate. It collects all violations of a schema.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap makes ValidationError usable with errors.Is and errors.As.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Violations))
+	for i, v := range e.Violations {
+		errs[i] = v
+	}
+	return errs
+}
+
+// Validate checks configuration conf against the schema. It reports all
+// violations at once by returning a *ValidationError.
+//
+// If conf implements EnumerableConfiguration, keys matching rules with
+// wildcards are validated as well, and keys not declared in the schema are
+// returned as warnings. For unknown keys which look like typos of declared
+// keys, a suggestion is made.
+func (s *Schema) Validate(conf Configuration) (warnings []Violation, err error) {
+	var verr ValidationError
+	ec, enumerable := conf.(EnumerableConfiguration)
+	for _, r := range s.rules {
+		if r.isPattern() {
+			if enumerable {
+				for _, key := range ec.Keys(r.fixedPrefix()) {
+					if r.matches(key) {
+						verr.Violations = append(verr.Violations, r.check(conf, key)...)
+					}
+				}
+			}
+			continue
+		}
+		if !isPresent(conf, r) {
+			if r.required {
+				verr.Violations = append(verr.Violations, Violation{Key: r.Key, Err: ErrKeyNotFound,
+					Reason: "required key is missing"})
+			}
+			continue
+		}
+		verr.Violations = append(verr.Violations, r.check(conf, r.Key)...)
+	}
+	if enumerable {
+		warnings = s.unknownKeys(ec.Keys(""))
+	}
+	if len(verr.Violations) > 0 {
+		return warnings, &verr
+	}
+	return warnings, nil
+}
+
+// isPresent is a predicate wether conf holds a value for the key of rule r.
+// Keys which are prefixes of other keys only do not count as present, except
+// for dictionaries.
+func isPresent(conf Configuration, r *Rule) bool {
+	if r.Type == TypeStringMap {
+		return conf.IsSet(r.Key) || Typed(conf).GetStringMap(r.Key) != nil
+	}
+	if !conf.IsSet(r.Key) {
+		return false
+	}
+	if ec, ok := conf.(EnumerableConfiguration); ok {
+		return isLeaf(ec, r.Key)
+	}
+	return true
+}
+
+// isLeaf is a predicate wether key holds a value, as opposed to being a prefix
+// of other keys only.
+func isLeaf(ec EnumerableConfiguration, key string) bool {
+	for _, k := range ec.Keys(key) {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// check validates the value for key against rule r.
+func (r *Rule) check(conf Configuration, key string) []Violation {
+	invalid := func(format string, args ...any) []Violation {
+		return []Violation{{Key: key, Err: ErrInvalidValue, Reason: fmt.Sprintf(format, args...)}}
+	}
+	sc := Strict(conf)
+	var num float64
+	var err error
+	switch r.Type {
+	case TypeString:
+		_, err = sc.LookupString(key)
+	case TypeInt:
+		var n int
+		n, err = sc.LookupInt(key)
+		num = float64(n)
+	case TypeBool:
+		_, err = sc.LookupBool(key)
+	case TypeFloat:
+		num, err = sc.LookupFloat64(key)
+	case TypeDuration:
+		_, err = sc.LookupDuration(key)
+	case TypeTime:
+		_, err = sc.LookupTime(key)
+	case TypeStringSlice:
+		_, err = sc.LookupStringSlice(key)
+	case TypeStringMap:
+		_, err = sc.LookupStringMap(key)
+	}
+	secret := r.secret || IsSecret(conf, key)
+	if err != nil {
+		if secret { // causes may quote the value
+			return invalid("expected value of type %s", r.Type)
+		}
+		return invalid("expected value of type %s: %s", r.Type, causeOf(err))
+	}
+	value, shown := conf.GetString(key), fmt.Sprintf("%q", conf.GetString(key))
+	if secret {
+		shown = Redacted
+	}
+	if r.oneOf != nil {
+		allowed := r.oneOf()
+		found := false
+		for _, a := range allowed {
+			if strings.EqualFold(a, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return invalid("value %s not one of %s", shown, strings.Join(allowed, ", "))
+		}
+	}
+	if r.hasRange && (r.Type == TypeInt || r.Type == TypeFloat) && (num < r.min || num > r.max) {
+		if secret {
+			return invalid("value %s out of range [%v…%v]", shown, r.min, r.max)
+		}
+		return invalid("value %v out of range [%v…%v]", num, r.min, r.max)
+	}
+	if r.pattern != nil && !r.pattern.MatchString(value) {
+		return invalid("value %s does not match %s", shown, r.pattern)
+	}
+	return nil
+}
+
+// causeOf describes the cause of a failed lookup.
+func causeOf(err error) string {
+	var lerr *LookupError
+	if errors.As(err, &lerr) && lerr.Cause != nil {
+		return lerr.Cause.Error()
+	}
+	return err.Error()
+}
+
+func (r *Rule) isPattern() bool {
+	return strings.Contains(r.Key, "*")
+}
+
+// fixedPrefix returns the segments of a key pattern up to the first wildcard.
+func (r *Rule) fixedPrefix() string {
+	prefix, _, _ := strings.Cut(r.Key, "*")
+	return strings.TrimRight(prefix, ".")
+}
+
+// matches is a predicate wether key matches the key pattern of r.
+func (r *Rule) matches(key string) bool {
+	pat := strings.Split(r.Key, ".")
+	segs := strings.Split(key, ".")
+	for i, p := range pat {
+		if i >= len(segs) {
+			return false
+		}
+		if p == "*" && i == len(pat)-1 {
+			return true
+		}
+		if p != "*" && p != segs[i] {
+			return false
+		}
+	}
+	return len(pat) == len(segs)
+}
+
+// unknownKeys reports all keys not matched by any rule.
+func (s *Schema) unknownKeys(keys []string) []Violation {
+	var warnings []Violation
+	for _, key := range keys {
+		known := false
+		for _, r := range s.rules {
+			if r.Key == key || (r.isPattern() && r.matches(key)) ||
+				(r.Type == TypeStringMap && MatchesPrefix(key, r.Key)) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			warnings = append(warnings, Violation{Key: key, Err: ErrUnknownKey,
+				Reason: "unknown key", Suggestion: s.suggest(key)})
+		}
+	}
+	return warnings
+}
+
+// suggest finds a declared key similar to key, if any.
+func (s *Schema) suggest(key string) string {
+	var candidates []string
+	best := len(key)/3 + 1 // tolerate about one typo per three characters
+	for _, r := range s.rules {
+		if r.isPattern() {
+			continue
+		}
+		d := levenshtein(key, r.Key)
+		if d < best {
+			best, candidates = d, []string{r.Key}
+		} else if d == best {
+			candidates = append(candidates, r.Key)
+		}
+	}
+	if len(candidates) == 0 || best > 2 {
+		return ""
+	}
+	sort.Strings(candidates)
+	return candidates[0]
+}
+
+// levenshtein computes the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
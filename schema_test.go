@@ -0,0 +1,62 @@
+package schuko_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/testconfig"
+)
+
+func TestValidateReportsAllViolations(t *testing.T) {
+	schema := schuko.NewSchema(
+		schuko.Setting("server.port", schuko.TypeInt, schuko.Required(), schuko.Range(1, 65535)),
+		schuko.Setting("server.host", schuko.TypeString, schuko.Matches(`^[a-z.]+$`)),
+		schuko.Setting("server.timeout", schuko.TypeDuration),
+		schuko.Setting("db.user", schuko.TypeString, schuko.Required()),
+		schuko.Setting("log.level", schuko.TypeString, schuko.OneOf("Debug", "Info", "Error")),
+		schuko.Setting("log.modules.*", schuko.TypeString, schuko.OneOf("Debug", "Info", "Error")),
+	)
+	conf := testconfig.Conf{
+		"server.port":     70000,
+		"server.host":     "Local_Host",
+		"server.timeout":  "soon",
+		"log.level":       "debug",
+		"log.modules.db":  "Verbose",
+		"log.modules.web": "Info",
+		"server.prot":     "tcp",
+	}
+	warnings, err := schema.Validate(conf)
+	var verr *schuko.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	t.Logf("error = %v", err)
+	if len(verr.Violations) != 5 {
+		t.Errorf("expected 5 violations, got %d", len(verr.Violations))
+	}
+	if !errors.Is(err, schuko.ErrKeyNotFound) || !errors.Is(err, schuko.ErrInvalidValue) {
+		t.Errorf("expected missing keys and invalid values to be reported")
+	}
+	if len(warnings) != 1 || warnings[0].Key != "server.prot" {
+		t.Fatalf("expected warning for unknown key server.prot, got %v", warnings)
+	}
+	if warnings[0].Suggestion != "server.port" {
+		t.Errorf("expected suggestion server.port, got %q", warnings[0].Suggestion)
+	}
+}
+
+func TestValidateValidConfiguration(t *testing.T) {
+	schema := schuko.NewSchema(
+		schuko.Setting("db", schuko.TypeStringMap, schuko.Required()),
+		schuko.Setting("hosts", schuko.TypeStringSlice),
+	)
+	conf := testconfig.Conf{
+		"db.host": "localhost",
+		"db.port": 5432,
+	}
+	warnings, err := schema.Validate(conf)
+	if err != nil || len(warnings) > 0 {
+		t.Errorf("expected configuration to be valid, got %v, warnings %v", err, warnings)
+	}
+}
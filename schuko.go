@@ -27,6 +27,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -91,7 +92,25 @@ func GetAdapterFromConfiguration(conf Configuration) any {
 //	config.<suffix>       // if no pattern
 //	.<tag>.<suffix>       // for $HOME only and no pattern
 //
-// Allowed file types are given as argument `suffixes`.
+// Allowed file types are given as argument `suffixes`. Without a pattern, files
+// with names like <tag>.<profile>.<suffix> are not considered (see ProfileFiles).
+//
+// Note: Names are matched exactly, up to the suffix. Earlier versions matched
+// any file starting with “config.”, “<tag>.” or “.<tag>.”, thus loading files
+// like config.local.nt or myapp.user.yaml as well. These are not found anymore;
+// clients should move such settings to a profile, to a drop-in file or give a
+// pattern.
+//
+// Files are returned from the first directory containing any matching file,
+// in merge order: files later in the list are meant to override values of files
+// earlier in the list. Within a directory, config.<suffix> comes first, then
+// <tag>.<suffix>, then .<tag>.<suffix>; files differing by suffix only are ordered
+// as given by `suffixes`. Without a pattern, drop-in files of a sub-directory
+// “conf.d” (see DropInDir) follow in lexical order of their names, e.g.
+//
+//	$HOME/.config/myapp/config.nt
+//	$HOME/.config/myapp/conf.d/10-db.nt
+//	$HOME/.config/myapp/conf.d/20-tracing.nt
 //
 // Example: An app uses the tag 'myapp'. On a *nix-system the configuration may
 // be searched for at
@@ -103,85 +122,97 @@ func GetAdapterFromConfiguration(conf Configuration) any {
 // On MacOS it would be searched for in
 //
 //	$HOME/Library/Application Support/MyApp/
+//
+// LocateConfig uses DefaultSearchPath. Clients who need other locations, e.g.
+// /etc/<tag>, or want to know which location a file has been found at, should
+// use a SearchPath.
 func LocateConfig(appTag string, pattern string, suffixes []string) []string {
-	//
-	if appTag == "" || len(suffixes) == 0 {
-		return nil
-	}
-	tag := strings.ToLower(appTag)
-
-	var d []fs.DirEntry
-	var found bool
-	var dir string
-	var dirs []string
+	return Paths(DefaultSearchPath().Locate(appTag, pattern, suffixes))
+}
 
-	homedir, errH := os.UserHomeDir()
-	confdir, err := os.UserConfigDir()
+// DropInDir is the name of a sub-directory of configuration directories, holding
+// drop-in configuration files (see LocateConfig).
+const DropInDir = "conf.d"
 
-	if err == nil && (errH == nil && confdir != homedir) {
-		dir = filepath.Join(confdir, appTag)
-		if d, err = os.ReadDir(dir); err == nil {
-			if found, dirs = dirMatch(dir, d, tag, pattern, suffixes); found {
-				return dirs
+// dirMatch finds the configuration files within directory dir, with entries d.
+// If dropins is set, files of a drop-in directory are included.
+func dirMatch(dir string, d []fs.DirEntry, tag, pattern string, suffixes []string, dropins bool) (bool, []string) {
+	type match struct {
+		path       string
+		rank, suff int
+	}
+	var m []match
+	for _, e := range d {
+		fname := filepath.Base(e.Name())
+		base := strings.TrimSuffix(fname, filepath.Ext(fname))
+		rank := -1
+		if pattern != "" {
+			if fm(pattern, fname) {
+				rank = 0
 			}
-		}
-		dir = filepath.Join(confdir, tag)
-		if d, err = os.ReadDir(dir); err == nil {
-			if found, dirs = dirMatch(dir, d, tag, pattern, suffixes); found {
-				return dirs
+		} else {
+			// files for profiles, e.g. myapp.prod.nt, are excluded
+			switch base {
+			case "config":
+				rank = 0
+			case tag:
+				rank = 1
+			case "." + tag:
+				rank = 2
 			}
 		}
-	}
-	if errH != nil {
-		return nil
-	}
-	dir = filepath.Join(homedir, ".config", tag)
-	if d, err = os.ReadDir(dir); err == nil {
-		// look for ~/.config/myapp/*
-		if found, dirs = dirMatch(dir, d, tag, pattern, suffixes); found {
-			return dirs
+		if s := suffixIndex(fname, suffixes); rank >= 0 && s >= 0 && !e.IsDir() {
+			m = append(m, match{path: filepath.Join(dir, fname), rank: rank, suff: s})
 		}
 	}
-	if d, err = os.ReadDir(homedir); err == nil {
-		// look for ~/.myapp.*
-		if found, dirs = dirMatch(homedir, d, tag, pattern, suffixes); found {
-			return dirs
+	sort.SliceStable(m, func(i, j int) bool {
+		if m[i].rank != m[j].rank {
+			return m[i].rank < m[j].rank
 		}
+		return m[i].suff < m[j].suff
+	})
+	files := make([]string, 0, len(m))
+	for _, x := range m {
+		files = append(files, x.path)
+	}
+	if pattern == "" && dropins {
+		files = append(files, dropIns(filepath.Join(dir, DropInDir), suffixes)...)
 	}
-	return nil
+	if len(files) > 0 {
+		return true, files
+	}
+	return false, nil
 }
 
-func dirMatch(dir string, d []fs.DirEntry, tag, pattern string, suffixes []string) (bool, []string) {
-	m := []string{}
-	glob1 := "config.*"
-	glob2 := tag + ".*"
-	glob3 := "." + tag + ".*"
+// dropIns returns the configuration files within a drop-in directory, in
+// lexical order. Hidden files are ignored.
+func dropIns(dir string, suffixes []string) []string {
+	d, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
 	for _, e := range d {
-		fname := filepath.Base(e.Name())
-		if pattern != "" {
-			if fm(pattern, fname) {
-				ext := strings.TrimLeft(filepath.Ext(fname), ".")
-				for _, s := range suffixes {
-					if ext == s {
-						m = append(m, filepath.Join(dir, fname))
-						break
-					}
-				}
-			}
-		} else if fm(glob1, fname) || fm(glob2, fname) || fm(glob3, fname) {
-			ext := strings.TrimLeft(filepath.Ext(fname), ".")
-			for _, s := range suffixes {
-				if ext == s {
-					m = append(m, filepath.Join(dir, fname))
-					break
-				}
-			}
+		fname := e.Name()
+		if e.IsDir() || strings.HasPrefix(fname, ".") || suffixIndex(fname, suffixes) < 0 {
+			continue
 		}
+		files = append(files, filepath.Join(dir, fname))
 	}
-	if len(m) > 0 {
-		return true, m
+	sort.Strings(files)
+	return files
+}
+
+// suffixIndex returns the position of the suffix of fname within suffixes,
+// or -1 if the suffix is not contained.
+func suffixIndex(fname string, suffixes []string) int {
+	ext := strings.TrimLeft(filepath.Ext(fname), ".")
+	for i, s := range suffixes {
+		if ext == s {
+			return i
+		}
 	}
-	return false, nil
+	return -1
 }
 
 func fm(pattern, name string) bool {
@@ -6,6 +6,7 @@ package schuko
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -70,3 +71,58 @@ func eraser(fname string, f *os.File, path []string) {
 		os.Remove(p)
 	}
 }
+
+func TestLocateConfigOrder(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	dir := filepath.Join(home, ".config", "ordertest")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"ordertest.yml", "ordertest.nt", "config.nt", ".ordertest.nt", "other.nt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := LocateConfig("ordertest", "", []string{"nt", "yml"})
+	var names []string
+	for _, f := range files {
+		names = append(names, filepath.Base(f))
+	}
+	expected := []string{"config.nt", "ordertest.nt", "ordertest.yml", ".ordertest.nt"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected files in merge order %v, got %v", expected, names)
+	}
+}
+
+func TestLocateConfigExcludesProfiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	dir := filepath.Join(home, ".config", "proftest")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"config.nt", "config.local.nt", "proftest.yaml", "proftest.user.yaml", "proftest.prod.nt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var names []string
+	for _, f := range LocateConfig("proftest", "", []string{"nt", "yaml"}) {
+		names = append(names, filepath.Base(f))
+	}
+	expected := []string{"config.nt", "proftest.yaml"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected only %v to be located, got %v", expected, names)
+	}
+	names = nil
+	for _, f := range LocateConfig("proftest", "config.*", []string{"nt"}) {
+		names = append(names, filepath.Base(f))
+	}
+	expected = []string{"config.local.nt", "config.nt"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected pattern to match %v, got %v", expected, names)
+	}
+}
@@ -0,0 +1,356 @@
+/*
+Package envadapter is for application configuration with environment variables.
+
+Configuration keys are mapped to names of environment variables by upper-casing
+them, replacing key separators ('.', '/' and '-') by a separator string, and prepending
+an application prefix. With prefix "MYAPP", key
+
+	tracing.adapter
+
+will be found in environment variable
+
+	MYAPP_TRACING_ADAPTER
+
+All configuration is started explicitely with a call to
+
+	conf := envadapter.New("MYAPP")
+
+The adapter plays well with trace2go: trace levels may be set by variables like
+MYAPP_TRACELEVEL_ROOT=Debug, given a call to
+
+	trace2go.ConfigureRoot(conf, "tracelevel")
+
+# License
+
+Governed by a 3-Clause BSD license. License file may be found in the root
+folder of this module.
+
+Copyright © Norbert Pillmayer <norbert@pillmayer.com>
+*/
+package envadapter
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/npillmayer/schuko"
+)
+
+// EConf represents a configuration read from environment variables.
+type EConf struct {
+	prefix   string            // prefix for variable names, e.g. "MYAPP"
+	sep      string            // separator for key segments in variable names
+	listSep  string            // separator for list items
+	bindings map[string]string // explicit key to variable name mappings
+}
+
+// Option is a type to influence the mapping of keys to environment variables.
+// Multiple options may be passed to `New(…)`.
+type Option func(*EConf)
+
+// Separator sets the string which separates key segments in names of environment
+// variables. Default is "_".
+func Separator(sep string) Option {
+	return func(c *EConf) {
+		c.sep = sep
+	}
+}
+
+// ListSeparator sets the string which separates items of lists within the value
+// of an environment variable. Default is ",".
+func ListSeparator(sep string) Option {
+	return func(c *EConf) {
+		c.listSep = sep
+	}
+}
+
+// Bind maps a configuration key to an environment variable with an explicit
+// name, regardless of prefix and separator rules.
+//
+//	conf := envadapter.New("MYAPP", envadapter.Bind("home.dir", "HOME"))
+func Bind(key string, name string) Option {
+	return func(c *EConf) {
+		c.bindings[key] = name
+	}
+}
+
+// New creates a new configuration adapter for environment variables.
+// prefix is prepended to variable names, separated by the separator
+// string. If prefix is empty, no prefix is used.
+func New(prefix string, opts ...Option) *EConf {
+	c := &EConf{
+		prefix:   strings.ToUpper(prefix),
+		sep:      "_",
+		listSep:  ",",
+		bindings: make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// --- Key mapping -----------------------------------------------------------
+
+var keySeparators = strings.NewReplacer(".", "|", "/", "|", "-", "|")
+
+// EnvName returns the name of the environment variable for a configuration key.
+func (c *EConf) EnvName(key string) string {
+	if name, ok := c.bindings[key]; ok {
+		return name
+	}
+	segments := strings.Split(keySeparators.Replace(key), "|")
+	if c.prefix != "" {
+		segments = append([]string{c.prefix}, segments...)
+	}
+	return strings.ToUpper(strings.Join(segments, c.sep))
+}
+
+// Key returns the configuration key for the name of an environment variable.
+// It is the reverse operation of EnvName, with the limitation that key separators
+// other than '.' cannot be reconstructed. If the variable name does not start
+// with the prefix, found is false.
+func (c *EConf) Key(name string) (key string, found bool) {
+	for k, n := range c.bindings {
+		if n == name {
+			return k, true
+		}
+	}
+	if c.prefix != "" {
+		var ok bool
+		if name, ok = strings.CutPrefix(name, c.prefix+c.sep); !ok {
+			return "", false
+		}
+	}
+	if name == "" {
+		return "", false
+	}
+	return strings.ToLower(strings.ReplaceAll(name, c.sep, ".")), true
+}
+
+// Mapping returns a table of environment variable names for a set of keys.
+// It is intended for documentation purposes, e.g. for printing the variables
+// an application will react to.
+func (c *EConf) Mapping(keys ...string) map[string]string {
+	m := make(map[string]string, len(keys))
+	for _, key := range keys {
+		m[key] = c.EnvName(key)
+	}
+	return m
+}
+
+// Environ returns all configuration keys, together with their values, for which
+// an environment variable is present.
+func (c *EConf) Environ() map[string]string {
+	m := make(map[string]string)
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if key, ok := c.Key(name); ok {
+			m[key] = value
+		}
+	}
+	return m
+}
+
+// --- Interface Configuration -----------------------------------------------
+
+// InitDefaults does nothing, as environment variables need not be initialized.
+func (c *EConf) InitDefaults() {}
+
+// IsSet is a predicate wether an environment variable for key is present.
+func (c *EConf) IsSet(key string) bool {
+	_, found := os.LookupEnv(c.EnvName(key))
+	return found
+}
+
+// GetString returns a configuration property as a string.
+func (c *EConf) GetString(key string) string {
+	v, _ := os.LookupEnv(c.EnvName(key))
+	return v
+}
+
+// GetInt returns a configuration property as an integer.
+func (c *EConf) GetInt(key string) int {
+	n, _ := schuko.ToInt(c.GetString(key))
+	return n
+}
+
+// GetBool returns a configuration property as a boolean value.
+func (c *EConf) GetBool(key string) bool {
+	b, _ := schuko.ToBool(c.GetString(key))
+	return b
+}
+
+// GetFloat64 returns a configuration property as a float.
+func (c *EConf) GetFloat64(key string) float64 {
+	f, _ := schuko.ToFloat64(c.GetString(key))
+	return f
+}
+
+// GetDuration returns a configuration property as a time.Duration.
+func (c *EConf) GetDuration(key string) time.Duration {
+	d, _ := schuko.ToDuration(c.GetString(key))
+	return d
+}
+
+// GetTime returns a configuration property as a time.Time.
+func (c *EConf) GetTime(key string) time.Time {
+	t, _ := schuko.ToTime(c.GetString(key))
+	return t
+}
+
+// GetStringSlice returns a configuration property as a list of strings.
+// The value of the environment variable is split at the list separator.
+func (c *EConf) GetStringSlice(key string) []string {
+	l, _ := c.LookupStringSlice(key)
+	return l
+}
+
+// GetStringMap returns a configuration property as a dictionary.
+// If no variable for key is present, all variables starting with the variable
+// name for key are collected, i.e. MYAPP_DB_HOST and MYAPP_DB_PORT for key "db".
+// MYAPP_DB_POOL_SIZE results in a nested dictionary {"pool": {"size": …}}.
+func (c *EConf) GetStringMap(key string) map[string]any {
+	m, _ := c.LookupStringMap(key)
+	return m
+}
+
+// LookupString returns a configuration property as a string, or an error
+// if the key is not set.
+func (c *EConf) LookupString(key string) (string, error) {
+	v, found := os.LookupEnv(c.EnvName(key))
+	return schuko.ConvertValue(key, v, found, schuko.ToString)
+}
+
+// LookupInt returns a configuration property as an integer, or an error
+// if the key is not set or the value cannot be converted.
+func (c *EConf) LookupInt(key string) (int, error) {
+	v, found := os.LookupEnv(c.EnvName(key))
+	return schuko.ConvertValue(key, v, found, schuko.ToInt)
+}
+
+// LookupBool returns a configuration property as a boolean value, or an error
+// if the key is not set or the value cannot be converted.
+func (c *EConf) LookupBool(key string) (bool, error) {
+	v, found := os.LookupEnv(c.EnvName(key))
+	return schuko.ConvertValue(key, v, found, schuko.ToBool)
+}
+
+// LookupFloat64 returns a configuration property as a float, or an error
+// if the key is not set or the value cannot be converted.
+func (c *EConf) LookupFloat64(key string) (float64, error) {
+	v, found := os.LookupEnv(c.EnvName(key))
+	return schuko.ConvertValue(key, v, found, schuko.ToFloat64)
+}
+
+// LookupDuration returns a configuration property as a time.Duration, or an error
+// if the key is not set or the value cannot be converted.
+func (c *EConf) LookupDuration(key string) (time.Duration, error) {
+	v, found := os.LookupEnv(c.EnvName(key))
+	return schuko.ConvertValue(key, v, found, schuko.ToDuration)
+}
+
+// LookupTime returns a configuration property as a time.Time, or an error
+// if the key is not set or the value cannot be converted.
+func (c *EConf) LookupTime(key string) (time.Time, error) {
+	v, found := os.LookupEnv(c.EnvName(key))
+	return schuko.ConvertValue(key, v, found, schuko.ToTime)
+}
+
+// LookupStringSlice returns a configuration property as a list of strings, or an error
+// if the key is not set.
+func (c *EConf) LookupStringSlice(key string) ([]string, error) {
+	v, found := os.LookupEnv(c.EnvName(key))
+	return schuko.ConvertValue(key, v, found, c.splitList)
+}
+
+// LookupStringMap returns a configuration property as a dictionary, or an error
+// if the key is not set or the value cannot be converted.
+func (c *EConf) LookupStringMap(key string) (map[string]any, error) {
+	v, found := os.LookupEnv(c.EnvName(key))
+	if !found {
+		if m := c.collectMap(key); m != nil {
+			return m, nil
+		}
+	}
+	return schuko.ConvertValue(key, v, found, schuko.ToStringMap)
+}
+
+// splitList splits a variable value at the list separator.
+func (c *EConf) splitList(v any) ([]string, error) {
+	s, _ := v.(string)
+	if strings.TrimSpace(s) == "" {
+		return []string{}, nil
+	}
+	l := strings.Split(s, c.listSep)
+	for i, item := range l {
+		l[i] = strings.TrimSpace(item)
+	}
+	return l, nil
+}
+
+// collectMap collects all variables with names starting with the variable name
+// for key. Sub-keys are split at separators into nested maps, as with other
+// adapters. Returns nil if no variable matches.
+func (c *EConf) collectMap(key string) map[string]any {
+	var flat map[string]any
+	prefix := c.EnvName(key) + c.sep
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if sub, ok := strings.CutPrefix(name, prefix); ok && sub != "" {
+			if flat == nil {
+				flat = make(map[string]any)
+			}
+			flat[strings.ToLower(strings.ReplaceAll(sub, c.sep, "."))] = value
+		}
+	}
+	if flat == nil {
+		return nil
+	}
+	return schuko.Unflatten(flat)
+}
+
+// Origin returns the environment variable supplying the value for key.
+func (c *EConf) Origin(key string) (schuko.Origin, bool) {
+	name := c.EnvName(key)
+	if _, found := os.LookupEnv(name); !found {
+		return schuko.Origin{}, false
+	}
+	return schuko.Origin{Kind: schuko.SourceEnv, Name: name}, true
+}
+
+// Origins returns the environment variables for all keys present (see Environ).
+func (c *EConf) Origins() map[string]schuko.Origin {
+	origins := make(map[string]schuko.Origin)
+	for key := range c.Environ() {
+		origins[key] = schuko.Origin{Kind: schuko.SourceEnv, Name: c.EnvName(key)}
+	}
+	return origins
+}
+
+// Keys returns the keys for which an environment variable is present (see
+// Environ), restricted to prefix.
+func (c *EConf) Keys(prefix string) []string {
+	env := c.Environ()
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	return schuko.FilterKeys(keys, prefix)
+}
+
+// AllSettings returns the values of all environment variables present (see
+// Environ) as a nested dictionary.
+func (c *EConf) AllSettings() map[string]any {
+	flat := make(map[string]any)
+	for key, v := range c.Environ() {
+		flat[key] = v
+	}
+	return schuko.Unflatten(flat)
+}
+
+var _ schuko.TypedConfiguration = &EConf{}
+var _ schuko.StrictConfiguration = &EConf{}
+var _ schuko.ProvenanceConfiguration = &EConf{}
+var _ schuko.EnumerableConfiguration = &EConf{}
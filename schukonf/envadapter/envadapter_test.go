@@ -0,0 +1,71 @@
+package envadapter_test
+
+import (
+	"testing"
+
+	"github.com/npillmayer/schuko/schukonf/envadapter"
+	"github.com/npillmayer/schuko/tracing"
+	"github.com/npillmayer/schuko/tracing/gologadapter"
+	"github.com/npillmayer/schuko/tracing/trace2go"
+)
+
+func TestKeyMapping(t *testing.T) {
+	c := envadapter.New("myapp")
+	if name := c.EnvName("tracing.adapter"); name != "MYAPP_TRACING_ADAPTER" {
+		t.Errorf("expected MYAPP_TRACING_ADAPTER, got %q", name)
+	}
+	if name := c.EnvName("tracelevel/my-module"); name != "MYAPP_TRACELEVEL_MY_MODULE" {
+		t.Errorf("expected MYAPP_TRACELEVEL_MY_MODULE, got %q", name)
+	}
+	if key, ok := c.Key("MYAPP_TRACING_DESTINATION"); !ok || key != "tracing.destination" {
+		t.Errorf("expected key tracing.destination, got %q", key)
+	}
+	if _, ok := c.Key("OTHER_TRACING_DESTINATION"); ok {
+		t.Errorf("expected variable without prefix not to map to a key")
+	}
+	c = envadapter.New("X", envadapter.Separator("__"), envadapter.Bind("home", "HOME"))
+	if name := c.EnvName("a.b"); name != "X__A__B" {
+		t.Errorf("expected X__A__B, got %q", name)
+	}
+	if name := c.EnvName("home"); name != "HOME" {
+		t.Errorf("expected bound variable HOME, got %q", name)
+	}
+}
+
+func TestValues(t *testing.T) {
+	t.Setenv("MYAPP_HOSTS", "a; b; c")
+	t.Setenv("MYAPP_PORT", "8080")
+	t.Setenv("MYAPP_DB_HOST", "localhost")
+	t.Setenv("MYAPP_DB_USER", "admin")
+	c := envadapter.New("MYAPP", envadapter.ListSeparator(";"))
+	if !c.IsSet("port") || c.GetInt("port") != 8080 {
+		t.Errorf("expected port 8080, got %d", c.GetInt("port"))
+	}
+	if l := c.GetStringSlice("hosts"); len(l) != 3 || l[1] != "b" {
+		t.Errorf("expected 3 hosts, got %v", l)
+	}
+	if m := c.GetStringMap("db"); m["host"] != "localhost" || m["user"] != "admin" {
+		t.Errorf("expected db map from variables, got %v", m)
+	}
+	if env := c.Environ(); env["db.user"] != "admin" {
+		t.Errorf("expected db.user in environment, got %v", env)
+	}
+}
+
+func TestTraceLevelFromEnv(t *testing.T) {
+	t.Setenv("MYAPP_TRACING_ADAPTER", "go")
+	t.Setenv("MYAPP_TRACELEVEL_ROOT", "Debug")
+	t.Setenv("MYAPP_TRACELEVEL_MY_MODULE", "Info")
+	tracing.RegisterTraceAdapter("go", gologadapter.GetAdapter(), false)
+	defer trace2go.Teardown()
+	if err := trace2go.ConfigureRoot(envadapter.New("MYAPP"), "tracelevel"); err != nil {
+		t.Fatal(err)
+	}
+	if l := trace2go.Root().GetTraceLevel(); l != tracing.LevelDebug {
+		t.Errorf("expected root tracer at level Debug, is %s", l)
+	}
+	tracer, _ := trace2go.NewTracer("my.module", true)
+	if l := tracer.GetTraceLevel(); l != tracing.LevelInfo {
+		t.Errorf("expected tracer my.module at level Info, is %s", l)
+	}
+}
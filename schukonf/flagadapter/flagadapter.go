@@ -0,0 +1,379 @@
+/*
+Package flagadapter is for application configuration with command-line flags,
+using the standard library's flag package.
+
+Configuration keys are mapped to flag names by replacing key separators ('.' and '/')
+with dashes. Key
+
+	tracing.adapter
+
+will be set by flag
+
+	--tracing-adapter
+
+All configuration is started explicitely with a call to
+
+	conf := flagadapter.New(flag.CommandLine)
+	err := conf.Register(flagadapter.Flag{Key: "tracing.adapter", Default: "go", Usage: "tracing adapter"})
+	…
+	flag.Parse()
+
+Flags are considered set (see IsSet) only if they have been passed on the command line.
+This makes FConf suitable as the top layer of a schuko.Layered configuration.
+
+# License
+
+Governed by a 3-Clause BSD license. License file may be found in the root
+folder of this module.
+
+Copyright © Norbert Pillmayer <norbert@pillmayer.com>
+*/
+package flagadapter
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/npillmayer/schuko"
+)
+
+// FConf represents a configuration read from command-line flags.
+type FConf struct {
+	fs   *flag.FlagSet
+	keys map[string]string // flag names to keys, as registered
+}
+
+// New creates a new configuration adapter for flag set fs. If fs is nil,
+// flag.CommandLine will be used.
+func New(fs *flag.FlagSet) *FConf {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	return &FConf{fs: fs, keys: make(map[string]string)}
+}
+
+// FlagSet returns the wrapped flag set.
+func (c *FConf) FlagSet() *flag.FlagSet {
+	return c.fs
+}
+
+// Flag declares a configuration key to be registered as a command-line flag.
+type Flag struct {
+	Key     string // configuration key, e.g. "tracing.adapter"
+	Default any    // default value; its type determines the type of the flag
+	Usage   string // help text
+}
+
+// ErrFlagCollision is reported by Register for keys which map to the name of
+// a flag already defined, e.g. "my-module" and "my.module".
+var ErrFlagCollision = errors.New("flag name collision")
+
+// Register defines flags for a set of configuration keys. The type of a flag
+// is derived from the type of the default value: bool, int, float64 and
+// time.Duration result in flags of the corresponding type, all other types in
+// string flags.
+//
+// As the mapping of keys to flag names is not unique (see FlagName), different
+// keys may result in the same flag name. Register then returns an error wrapping
+// ErrFlagCollision and defines none of the flags.
+//
+// Register has to be called before the flag set is parsed.
+func (c *FConf) Register(flags ...Flag) error {
+	names := make(map[string]string, len(flags))
+	for _, f := range flags {
+		name := FlagName(f.Key)
+		if key, found := names[name]; found {
+			return fmt.Errorf("%w: keys %q and %q both map to flag --%s", ErrFlagCollision, key, f.Key, name)
+		}
+		if key, found := c.keys[name]; found {
+			return fmt.Errorf("%w: keys %q and %q both map to flag --%s", ErrFlagCollision, key, f.Key, name)
+		}
+		if c.fs.Lookup(name) != nil {
+			return fmt.Errorf("%w: flag --%s for key %q is already defined", ErrFlagCollision, name, f.Key)
+		}
+		names[name] = f.Key
+	}
+	for _, f := range flags {
+		name := FlagName(f.Key)
+		c.keys[name] = f.Key
+		switch d := f.Default.(type) {
+		case bool:
+			c.fs.Bool(name, d, f.Usage)
+		case int:
+			c.fs.Int(name, d, f.Usage)
+		case float64:
+			c.fs.Float64(name, d, f.Usage)
+		case time.Duration:
+			c.fs.Duration(name, d, f.Usage)
+		case nil:
+			c.fs.String(name, "", f.Usage)
+		default:
+			c.fs.String(name, fmt.Sprintf("%v", d), f.Usage)
+		}
+	}
+	return nil
+}
+
+// FlagName returns the name of the flag for a configuration key. The mapping
+// is lossy: keys "my-module", "my.module" and "my/module" all map to flag
+// name "my-module".
+func FlagName(key string) string {
+	return strings.NewReplacer(".", "-", "/", "-").Replace(key)
+}
+
+// Key returns the configuration key for a flag name. Dashes are interpreted as
+// key separators. For flags defined by Register, use FConf.Key, which returns
+// the key as registered.
+func Key(flagName string) string {
+	return strings.ReplaceAll(strings.TrimLeft(flagName, "-"), "-", ".")
+}
+
+// Key returns the configuration key for a flag name, as registered. For flags
+// not defined by Register, package function Key is used.
+func (c *FConf) Key(flagName string) string {
+	if key, found := c.keys[strings.TrimLeft(flagName, "-")]; found {
+		return key
+	}
+	return Key(flagName)
+}
+
+// Passed returns the configuration keys of all flags passed on the command line.
+func (c *FConf) Passed() []string {
+	var keys []string
+	c.fs.Visit(func(f *flag.Flag) {
+		keys = append(keys, c.Key(f.Name))
+	})
+	return keys
+}
+
+// --- Interface Configuration -----------------------------------------------
+
+// InitDefaults does nothing. Defaults are set by defining flags.
+func (c *FConf) InitDefaults() {}
+
+// IsSet is a predicate wether the flag for key has been passed on the command line.
+// Flags which are defined, but have not been passed, are not considered to be set.
+func (c *FConf) IsSet(key string) bool {
+	name := FlagName(key)
+	isSet := false
+	c.fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			isSet = true
+		}
+	})
+	return isSet
+}
+
+// value returns the value of the flag for key, either as passed on the command
+// line or its default value. If the flag value implements flag.Getter, the value
+// will be of the type of the flag.
+func (c *FConf) value(key string) (any, bool) {
+	f := c.fs.Lookup(FlagName(key))
+	if f == nil {
+		return nil, false
+	}
+	if g, ok := f.Value.(flag.Getter); ok {
+		return g.Get(), true
+	}
+	return f.Value.String(), true
+}
+
+// lookup is the strict version of value: flags which have not been passed on
+// the command line are reported as not found.
+func (c *FConf) lookup(key string) (any, bool) {
+	if !c.IsSet(key) {
+		return nil, false
+	}
+	return c.value(key)
+}
+
+// GetString returns a configuration property as a string. For flags not passed
+// on the command line, the default value is returned.
+func (c *FConf) GetString(key string) string {
+	f := c.fs.Lookup(FlagName(key))
+	if f == nil {
+		return ""
+	}
+	return f.Value.String()
+}
+
+// GetInt returns a configuration property as an integer.
+func (c *FConf) GetInt(key string) int {
+	v, _ := c.value(key)
+	n, _ := schuko.ToInt(v)
+	return n
+}
+
+// GetBool returns a configuration property as a boolean value.
+func (c *FConf) GetBool(key string) bool {
+	v, _ := c.value(key)
+	b, _ := schuko.ToBool(v)
+	return b
+}
+
+// GetFloat64 returns a configuration property as a float.
+func (c *FConf) GetFloat64(key string) float64 {
+	v, _ := c.value(key)
+	f, _ := schuko.ToFloat64(v)
+	return f
+}
+
+// GetDuration returns a configuration property as a time.Duration.
+func (c *FConf) GetDuration(key string) time.Duration {
+	v, _ := c.value(key)
+	d, _ := schuko.ToDuration(v)
+	return d
+}
+
+// GetTime returns a configuration property as a time.Time.
+func (c *FConf) GetTime(key string) time.Time {
+	v, _ := c.value(key)
+	t, _ := schuko.ToTime(v)
+	return t
+}
+
+// GetStringSlice returns a configuration property as a list of strings.
+func (c *FConf) GetStringSlice(key string) []string {
+	v, found := c.value(key)
+	if !found {
+		return nil
+	}
+	l, _ := schuko.ToStringSlice(v)
+	return l
+}
+
+// GetStringMap returns a configuration property as a dictionary.
+// If no flag for key is defined, the values of all flags for keys starting with
+// "key." are collected into nested maps, e.g. flags --db-host and --db-port
+// for key "db".
+func (c *FConf) GetStringMap(key string) map[string]any {
+	v, found := c.value(key)
+	if !found {
+		return c.collectMap(key, c.fs.VisitAll)
+	}
+	m, _ := schuko.ToStringMap(v)
+	return m
+}
+
+// collectMap collects the values of flags for keys starting with "key.",
+// visiting flags with visit (either VisitAll or Visit of the flag set).
+// Returns nil if no flag matches.
+func (c *FConf) collectMap(key string, visit func(func(*flag.Flag))) map[string]any {
+	var flat map[string]any
+	visit(func(f *flag.Flag) {
+		if sub, ok := strings.CutPrefix(c.Key(f.Name), key+"."); ok && sub != "" {
+			if flat == nil {
+				flat = make(map[string]any)
+			}
+			flat[sub], _ = c.value(c.Key(f.Name))
+		}
+	})
+	if flat == nil {
+		return nil
+	}
+	return schuko.Unflatten(flat)
+}
+
+// LookupString returns a configuration property as a string, or an error
+// if the flag has not been passed.
+func (c *FConf) LookupString(key string) (string, error) {
+	v, found := c.lookup(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToString)
+}
+
+// LookupInt returns a configuration property as an integer, or an error
+// if the flag has not been passed or the value cannot be converted.
+func (c *FConf) LookupInt(key string) (int, error) {
+	v, found := c.lookup(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToInt)
+}
+
+// LookupBool returns a configuration property as a boolean value, or an error
+// if the flag has not been passed or the value cannot be converted.
+func (c *FConf) LookupBool(key string) (bool, error) {
+	v, found := c.lookup(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToBool)
+}
+
+// LookupFloat64 returns a configuration property as a float, or an error
+// if the flag has not been passed or the value cannot be converted.
+func (c *FConf) LookupFloat64(key string) (float64, error) {
+	v, found := c.lookup(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToFloat64)
+}
+
+// LookupDuration returns a configuration property as a time.Duration, or an error
+// if the flag has not been passed or the value cannot be converted.
+func (c *FConf) LookupDuration(key string) (time.Duration, error) {
+	v, found := c.lookup(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToDuration)
+}
+
+// LookupTime returns a configuration property as a time.Time, or an error
+// if the flag has not been passed or the value cannot be converted.
+func (c *FConf) LookupTime(key string) (time.Time, error) {
+	v, found := c.lookup(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToTime)
+}
+
+// LookupStringSlice returns a configuration property as a list of strings, or an error
+// if the flag has not been passed or the value cannot be converted.
+func (c *FConf) LookupStringSlice(key string) ([]string, error) {
+	v, found := c.lookup(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToStringSlice)
+}
+
+// LookupStringMap returns a configuration property as a dictionary, or an error
+// if the flag has not been passed or the value cannot be converted. As with
+// GetStringMap, flags for keys below key are collected, if passed.
+func (c *FConf) LookupStringMap(key string) (map[string]any, error) {
+	if c.fs.Lookup(FlagName(key)) == nil {
+		if m := c.collectMap(key, c.fs.Visit); m != nil {
+			return m, nil
+		}
+	}
+	v, found := c.lookup(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToStringMap)
+}
+
+// Origin returns the flag supplying the value for key, if it has been passed on
+// the command line.
+func (c *FConf) Origin(key string) (schuko.Origin, bool) {
+	if !c.IsSet(key) {
+		return schuko.Origin{}, false
+	}
+	return schuko.Origin{Kind: schuko.SourceFlag, Name: "--" + FlagName(key)}, true
+}
+
+// Origins returns the flags for all keys passed on the command line.
+func (c *FConf) Origins() map[string]schuko.Origin {
+	origins := make(map[string]schuko.Origin)
+	c.fs.Visit(func(f *flag.Flag) {
+		origins[c.Key(f.Name)] = schuko.Origin{Kind: schuko.SourceFlag, Name: "--" + f.Name}
+	})
+	return origins
+}
+
+// Keys returns the keys of all flags passed on the command line, restricted
+// to prefix.
+func (c *FConf) Keys(prefix string) []string {
+	return schuko.FilterKeys(c.Passed(), prefix)
+}
+
+// AllSettings returns the values of all flags passed on the command line as a
+// nested dictionary.
+func (c *FConf) AllSettings() map[string]any {
+	flat := make(map[string]any)
+	for _, key := range c.Passed() {
+		flat[key], _ = c.value(key)
+	}
+	return schuko.Unflatten(flat)
+}
+
+var _ schuko.TypedConfiguration = &FConf{}
+var _ schuko.StrictConfiguration = &FConf{}
+var _ schuko.ProvenanceConfiguration = &FConf{}
+var _ schuko.EnumerableConfiguration = &FConf{}
@@ -0,0 +1,90 @@
+package flagadapter_test
+
+import (
+	"errors"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/flagadapter"
+	"github.com/npillmayer/schuko/schukonf/testconfig"
+)
+
+func newConf(t *testing.T, args ...string) *flagadapter.FConf {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	c := flagadapter.New(fs)
+	err := c.Register(
+		flagadapter.Flag{Key: "tracing.adapter", Default: "go", Usage: "tracing adapter"},
+		flagadapter.Flag{Key: "server.port", Default: 8080, Usage: "port to listen on"},
+		flagadapter.Flag{Key: "timeout", Default: time.Second, Usage: "request timeout"},
+		flagadapter.Flag{Key: "verbose", Default: false, Usage: "verbose output"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestFlags(t *testing.T) {
+	c := newConf(t, "--tracing-adapter", "logrus", "--verbose", "-timeout=1.5s")
+	if !c.IsSet("tracing.adapter") || c.GetString("tracing.adapter") != "logrus" {
+		t.Errorf("expected tracing.adapter = logrus, got %q", c.GetString("tracing.adapter"))
+	}
+	if c.IsSet("server.port") {
+		t.Errorf("expected server.port not to be set, as it has not been passed")
+	}
+	if p := c.GetInt("server.port"); p != 8080 {
+		t.Errorf("expected default port 8080, got %d", p)
+	}
+	if _, err := c.LookupInt("server.port"); !errors.Is(err, schuko.ErrKeyNotFound) {
+		t.Errorf("expected strict lookup of default to fail, got %v", err)
+	}
+	if !c.GetBool("verbose") || c.GetDuration("timeout") != 1500*time.Millisecond {
+		t.Errorf("expected verbose and timeout of 1.5s")
+	}
+	if keys := c.Passed(); len(keys) != 3 {
+		t.Errorf("expected 3 flags passed, got %v", keys)
+	}
+}
+
+func TestFlagsAsLayer(t *testing.T) {
+	c := newConf(t, "--tracing-adapter", "logrus")
+	conf := schuko.NewLayered(
+		schuko.Layer{Name: "flags", Conf: c},
+		schuko.Layer{Name: "defaults", Conf: testconfig.Conf{"server.port": 9000, "tracing.adapter": "go"}},
+	)
+	if a := conf.GetString("tracing.adapter"); a != "logrus" {
+		t.Errorf("expected flag to override default, got %q", a)
+	}
+	if p := conf.GetInt("server.port"); p != 9000 {
+		t.Errorf("expected flag not passed to fall through, got port %d", p)
+	}
+}
+
+func TestRegisterCollision(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	c := flagadapter.New(fs)
+	err := c.Register(flagadapter.Flag{Key: "my-module", Default: "a"}, flagadapter.Flag{Key: "my.module", Default: "b"})
+	if !errors.Is(err, flagadapter.ErrFlagCollision) {
+		t.Errorf("expected collision of my-module and my.module, got %v", err)
+	}
+	if fs.Lookup("my-module") != nil {
+		t.Errorf("expected no flag to be defined after a collision")
+	}
+	if err := c.Register(flagadapter.Flag{Key: "my-module", Default: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Register(flagadapter.Flag{Key: "my/module"}); !errors.Is(err, flagadapter.ErrFlagCollision) {
+		t.Errorf("expected collision with registered key my-module, got %v", err)
+	}
+	if err := fs.Parse([]string{"--my-module", "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if keys := c.Passed(); len(keys) != 1 || keys[0] != "my-module" {
+		t.Errorf("expected passed key to be reported as registered, got %v", keys)
+	}
+}
@@ -0,0 +1,42 @@
+package koanfadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/npillmayer/schuko"
+)
+
+// Export writes the effective configuration of conf to w. format is a file
+// extension for which a parser is registered (see RegisterParser), e.g. "nt"
+// for NestedText or "json". JSON output is indented for readability.
+// Values of secret keys (see schuko.IsSecret) are redacted.
+//
+// conf has to implement schuko.EnumerableConfiguration, otherwise
+// schuko.ErrNotEnumerable is returned.
+func Export(w io.Writer, conf schuko.Configuration, format string) error {
+	ec, ok := conf.(schuko.EnumerableConfiguration)
+	if !ok {
+		return schuko.ErrNotEnumerable
+	}
+	parser, ok := ParserFor(format)
+	if !ok {
+		return fmt.Errorf("%w %q-files", ErrUnknownFormat, format)
+	}
+	b, err := parser.Marshal(schuko.Redact(conf, ec.AllSettings()))
+	if err != nil {
+		return err
+	}
+	if normalizeExt(format) == "json" || normalizeExt(format) == "jsn" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, b, "", "    "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		b = buf.Bytes()
+	}
+	_, err = w.Write(b)
+	return err
+}
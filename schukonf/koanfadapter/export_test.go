@@ -0,0 +1,71 @@
+package koanfadapter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/koanfadapter"
+)
+
+func TestExportEffectiveConfiguration(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "name: test\ndb:\n    host: db.local\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.InitDefaults()
+	c.Set("db.port", "5432")
+	if keys := c.Keys("db"); !reflect.DeepEqual(keys, []string{"db.host", "db.port"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	var buf bytes.Buffer
+	if err := koanfadapter.Export(&buf, c, "nt"); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("exported:\n%s", buf.String())
+	m, err := koanfadapter.Parser().Unmarshal(buf.Bytes())
+	if err != nil {
+		t.Fatalf("expected export to be valid NestedText: %v", err)
+	}
+	if !reflect.DeepEqual(m, c.AllSettings()) {
+		t.Errorf("expected export to round-trip, got %v", m)
+	}
+	buf.Reset()
+	if err := koanfadapter.Export(&buf, c, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var j map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &j); err != nil || j["name"] != "test" {
+		t.Errorf("expected valid JSON export, got %q (%v)", buf.String(), err)
+	}
+}
+
+// plainConf hides all capabilities of a configuration beyond schuko.Configuration.
+type plainConf struct {
+	schuko.Configuration
+}
+
+func TestExportNeedsEnumeration(t *testing.T) {
+	var buf bytes.Buffer
+	err := koanfadapter.Export(&buf, plainConf{koanfadapter.New(nil, "", nil)}, "nt")
+	if !errors.Is(err, schuko.ErrNotEnumerable) {
+		t.Errorf("expected ErrNotEnumerable, got %v", err)
+	}
+}
+
+func TestExportRedactsSecrets(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "db:\n    user: admin\n    password: geheim\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.InitDefaults()
+	var buf bytes.Buffer
+	if err := koanfadapter.Export(&buf, c, "nt"); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); strings.Contains(out, "geheim") || !strings.Contains(out, "admin") {
+		t.Errorf("expected password to be redacted in export, got %q", out)
+	}
+}
@@ -1 +1,29 @@
 package koanfadapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/npillmayer/schuko/schukonf/koanfadapter"
+)
+
+func TestTypedGetters(t *testing.T) {
+	c := koanfadapter.New(nil, "", nil)
+	c.Set("timeout", "1.5s")
+	c.Set("ratio", "0.75")
+	c.Set("hosts", "a, b, c")
+	c.Set("db.host", "localhost")
+	c.Set("db.port", 5432)
+	if d := c.GetDuration("timeout"); d != 1500*time.Millisecond {
+		t.Errorf("expected timeout of 1.5s, got %v", d)
+	}
+	if f := c.GetFloat64("ratio"); f != 0.75 {
+		t.Errorf("expected ratio of 0.75, got %v", f)
+	}
+	if l := c.GetStringSlice("hosts"); len(l) != 3 || l[2] != "c" {
+		t.Errorf("expected 3 hosts, got %v", l)
+	}
+	if m := c.GetStringMap("db"); m["host"] != "localhost" || m["port"] != 5432 {
+		t.Errorf("expected db map with host and port, got %v", m)
+	}
+}
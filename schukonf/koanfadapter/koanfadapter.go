@@ -24,25 +24,38 @@ NestedText (see https://nestedtext.org) as a configuration format.
 Koanf needs parsers to implement the koanf.Parser interface.
 
 The parser is also available at `knadh/koanf.parsers.nestedtext`.
+
+Parsers for JSON, YAML and TOML are bundled as well. Clients may add parsers for
+other formats with RegisterParser.
 */
 package koanfadapter
 
 import (
-	"fmt"
+	"errors"
 	"log"
-	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/knadh/koanf"
 	"github.com/knadh/koanf/providers/confmap"
-	"github.com/knadh/koanf/providers/file"
 	"github.com/npillmayer/schuko"
 )
 
 // KConf respresents a koanf.Koanf configuration.
 type KConf struct {
-	k        *koanf.Koanf
-	tag      string
-	suffixes []string
+	loadMx    sync.Mutex   // serializes loading of files, i.e. Load…-methods and Reload
+	mx        sync.RWMutex // guards k, files, explicit, overrides, origins, profile and search
+	k         *koanf.Koanf
+	tag       string
+	suffixes  []string
+	files     []string                 // configuration files loaded
+	explicit  []string                 // configuration files loaded by LoadFile
+	overrides map[string]any           // values set by Set(…)
+	origins   map[string]schuko.Origin // provenance of values, by key
+	profile   string                   // profile applied by LoadProfile
+	search    *schuko.SearchPath       // locations of configuration files; nil for default
+	subMx     sync.Mutex               // guards subs
+	subs      []*subscription
 }
 
 // New creates a new koanf configuration adapter. If k is nil, a new Koanf
@@ -59,11 +72,23 @@ func New(k *koanf.Koanf, appTag string, suffixes []string) *KConf {
 		k:        k,
 		tag:      appTag,
 		suffixes: suffixes,
+		origins:  make(map[string]schuko.Origin),
 	}
 }
 
 // Koanf returns the embedded Koanf configuration-object.
+//
+// Please note that after a call to Reload or Set, the embedded Koanf may have
+// been replaced by a new instance.
 func (c *KConf) Koanf() *koanf.Koanf {
+	return c.konf()
+}
+
+// konf returns the current Koanf instance. Instances are never modified after
+// having been replaced, making it safe to use them without holding a lock.
+func (c *KConf) konf() *koanf.Koanf {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
 	return c.k
 }
 
@@ -75,10 +100,13 @@ func (c *KConf) Koanf() *koanf.Koanf {
 //
 //	“natural” configuration locations, if any are found. It does this by calling
 //	`InitFromDefaultFile()`
+//
+// InitDefaults does not report errors. Clients who want to be informed about
+// configuration files failing to load should call Init instead.
 func (c *KConf) InitDefaults() {
-	c.k.Load(confmap.Provider(map[string]any{
-		"tracing.adapter": "go",
-	}, c.k.Delim()), nil)
+	c.mx.Lock()
+	loadDefaults(c.k, c.origins)
+	c.mx.Unlock()
 	if c.tag != "" {
 		c.InitFromDefaultFile()
 	}
@@ -90,59 +118,154 @@ func (c *KConf) InitDefaults() {
 // Clients can suppress this behaviour by providing an empty appTag or
 // an empty suffixes array during creation of the adapter.
 //
+// Files are merged in the order located, i.e. later files override values of
+// earlier ones, and drop-in files in “conf.d” override the main configuration
+// file. Files may include other files (see IncludeKey).
+//
+// The format of a file is determined by its extension (see RegisterParser).
+// Files with extensions for which no parser is registered, as well as files
+// which fail to load, are reported to the Go standard logger and skipped.
+// Clients interested in errors should call LoadDefaultFiles instead.
+//
 // InitFromDefaultFile is usually not called directly by clients, but
 // rather by InitDefaults. It is made public to enable clients to override
 // it.
 func (c *KConf) InitFromDefaultFile() {
-	files := schuko.LocateConfig(c.tag, "", c.suffixes)
-	if len(files) == 0 {
-		return
-	}
-	for _, path := range files {
-		ext := filepath.Ext(path)
-		switch ext {
-		case ".jsn", ".json":
-			panic(fmt.Sprintf("do not know how to decode %q-files (%q)", ext, path))
-		case ".yml", "yaml":
-			panic(fmt.Sprintf("do not know how to decode %q-files (%q)", ext, path))
-		case ".tml", ".toml":
-			panic(fmt.Sprintf("do not know how to decode %q-files (%q)", ext, path))
-		case ".nt":
-			//TODO remove dependency to go log
-			if err := c.k.Load(file.Provider(path), Parser()); err != nil {
-				log.Fatalf("error loading NestedText format: %v", err)
+	if err := c.LoadDefaultFiles(BestEffort); err != nil {
+		var lerr *LoadError
+		if errors.As(err, &lerr) {
+			for _, ferr := range lerr.Errors {
+				if errors.Is(ferr, ErrUnknownFormat) {
+					log.Printf("skipping configuration file: %v", ferr)
+					continue
+				}
+				log.Printf("error loading configuration: %v", ferr)
 			}
-		default:
-			panic(fmt.Sprintf("do not know how to decode %q-files (%q)", ext, path))
 		}
 	}
 }
 
 // Set overrides any configuration values set from the environment.
+// Values set will survive a reload of configuration files.
 func (c *KConf) Set(key string, value any) {
-	c.k.Load(confmap.Provider(map[string]any{
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	if c.overrides == nil {
+		c.overrides = make(map[string]any)
+	}
+	c.overrides[key] = value
+	k := c.k.Copy()
+	k.Load(confmap.Provider(map[string]any{
 		key: value,
-	}, c.k.Delim()), nil)
+	}, k.Delim()), nil)
+	c.k = k
+	c.origins[key] = schuko.Origin{Kind: schuko.SourceSet}
 }
 
 // IsSet is a predicate wether a configuration flag is set to true.
 func (c *KConf) IsSet(key string) bool {
-	return c.k.Exists(key)
+	return c.konf().Exists(key)
 }
 
 // GetString returns a configuration property as a string.
 func (c *KConf) GetString(key string) string {
-	return c.k.String(key)
+	return c.konf().String(key)
 }
 
 // GetInt returns a configuration property as an integer.
 func (c *KConf) GetInt(key string) int {
-	return c.k.Int(key)
+	return c.konf().Int(key)
 }
 
 // GetBool returns a configuration property as a boolean value.
 func (c *KConf) GetBool(key string) bool {
-	return c.k.Bool(key)
+	return c.konf().Bool(key)
+}
+
+// GetFloat64 returns a configuration property as a float.
+func (c *KConf) GetFloat64(key string) float64 {
+	f, _ := schuko.ToFloat64(c.konf().Get(key))
+	return f
+}
+
+// GetDuration returns a configuration property as a time.Duration.
+func (c *KConf) GetDuration(key string) time.Duration {
+	d, _ := schuko.ToDuration(c.konf().Get(key))
+	return d
+}
+
+// GetTime returns a configuration property as a time.Time.
+func (c *KConf) GetTime(key string) time.Time {
+	t, _ := schuko.ToTime(c.konf().Get(key))
+	return t
+}
+
+// GetStringSlice returns a configuration property as a list of strings.
+func (c *KConf) GetStringSlice(key string) []string {
+	l, _ := schuko.ToStringSlice(c.konf().Get(key))
+	return l
+}
+
+// GetStringMap returns a configuration property as a dictionary.
+func (c *KConf) GetStringMap(key string) map[string]any {
+	m, _ := schuko.ToStringMap(c.konf().Get(key))
+	return m
+}
+
+// LookupString returns a configuration property as a string, or an error
+// if the key is not set or the value cannot be converted.
+func (c *KConf) LookupString(key string) (string, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToString)
+}
+
+// LookupInt returns a configuration property as an integer, or an error
+// if the key is not set or the value cannot be converted.
+func (c *KConf) LookupInt(key string) (int, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToInt)
+}
+
+// LookupBool returns a configuration property as a boolean value, or an error
+// if the key is not set or the value cannot be converted.
+func (c *KConf) LookupBool(key string) (bool, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToBool)
+}
+
+// LookupFloat64 returns a configuration property as a float, or an error
+// if the key is not set or the value cannot be converted.
+func (c *KConf) LookupFloat64(key string) (float64, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToFloat64)
+}
+
+// LookupDuration returns a configuration property as a time.Duration, or an error
+// if the key is not set or the value cannot be converted.
+func (c *KConf) LookupDuration(key string) (time.Duration, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToDuration)
+}
+
+// LookupTime returns a configuration property as a time.Time, or an error
+// if the key is not set or the value cannot be converted.
+func (c *KConf) LookupTime(key string) (time.Time, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToTime)
+}
+
+// LookupStringSlice returns a configuration property as a list of strings, or an error
+// if the key is not set or the value cannot be converted.
+func (c *KConf) LookupStringSlice(key string) ([]string, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToStringSlice)
+}
+
+// LookupStringMap returns a configuration property as a dictionary, or an error
+// if the key is not set or the value cannot be converted.
+func (c *KConf) LookupStringMap(key string) (map[string]any, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToStringMap)
 }
 
 // IsInteractive is a predicate: are we running in interactive mode?
@@ -152,4 +275,30 @@ func (c *KConf) IsInteractive() bool {
 	return true
 }
 
-var _ schuko.Configuration = &KConf{}
+// Keys returns the keys of all values of the configuration, restricted to prefix.
+func (c *KConf) Keys(prefix string) []string {
+	all := c.konf().All()
+	keys := make([]string, 0, len(all))
+	for key := range all {
+		keys = append(keys, key)
+	}
+	return schuko.FilterKeys(keys, prefix)
+}
+
+// AllSettings returns all values of the configuration as a nested dictionary.
+func (c *KConf) AllSettings() map[string]any {
+	return c.konf().Raw()
+}
+
+var _ schuko.TypedConfiguration = &KConf{}
+var _ schuko.StrictConfiguration = &KConf{}
+var _ schuko.ProvenanceConfiguration = &KConf{}
+var _ schuko.EnumerableConfiguration = &KConf{}
+
+func (c *KConf) raw(key string) (any, bool) {
+	k := c.konf()
+	if !k.Exists(key) {
+		return nil, false
+	}
+	return k.Get(key), true
+}
@@ -0,0 +1,347 @@
+package koanfadapter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/npillmayer/nestext"
+	"github.com/npillmayer/schuko"
+)
+
+// ErrUnknownFormat is reported for configuration files with an extension for
+// which no parser is registered.
+var ErrUnknownFormat = errors.New("do not know how to decode")
+
+// LoadMode determines how loading of multiple configuration files reacts to
+// errors.
+type LoadMode int
+
+const (
+	// FailFast stops at the first file failing to load. The configuration is
+	// left unchanged.
+	FailFast LoadMode = iota
+	// BestEffort loads all files possible, skipping files which fail to load.
+	BestEffort
+)
+
+// FileError describes a configuration file which failed to load.
+type FileError struct {
+	Path   string // path of the configuration file
+	Format string // format of the file, i.e. its extension without the dot
+	Line   int    // line of a format error, if known; 0 otherwise
+	Column int    // column of a format error, if known; 0 otherwise
+	Err    error  // underlying error
+}
+
+func (e *FileError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("config file %q (%s) at line %d, column %d: %s", e.Path, e.Format,
+			e.Line, e.Column, e.Err.Error())
+	}
+	return fmt.Sprintf("config file %q (%s): %s", e.Path, e.Format, e.Err.Error())
+}
+
+// Unwrap makes FileError usable with errors.Is and errors.As.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// LoadError aggregates all errors occuring while loading configuration files.
+type LoadError struct {
+	Errors []*FileError
+}
+
+func (e *LoadError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, ferr := range e.Errors {
+		msgs[i] = ferr.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap makes LoadError usable with errors.Is and errors.As.
+func (e *LoadError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, ferr := range e.Errors {
+		errs[i] = ferr
+	}
+	return errs
+}
+
+// Init is the error-reporting variant of InitDefaults. It loads built-in
+// defaults and application-specific configuration files (see LoadDefaultFiles).
+// If any file fails to load, Init returns a *LoadError.
+func (c *KConf) Init(mode LoadMode) error {
+	c.mx.Lock()
+	loadDefaults(c.k, c.origins)
+	c.mx.Unlock()
+	if c.tag == "" {
+		return nil
+	}
+	return c.LoadDefaultFiles(mode)
+}
+
+// LoadDefaultFiles is the error-reporting variant of InitFromDefaultFile.
+// It loads all configuration files found at the “natural” configuration
+// locations, as determined by schuko.LocateConfig, or by the search path set
+// with SetSearchPath.
+//
+// With mode FailFast, loading stops at the first file failing to load and
+// the configuration is left unchanged. With mode BestEffort, every file is
+// attempted and files failing to load are skipped. In both cases, errors are
+// reported as a *LoadError, containing a *FileError for every file which failed
+// to load.
+func (c *KConf) LoadDefaultFiles(mode LoadMode) error {
+	c.loadMx.Lock()
+	defer c.loadMx.Unlock()
+	files := c.locate()
+	if len(files) == 0 {
+		return nil
+	}
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	k, origins := c.k.Copy(), copyOrigins(c.origins)
+	var lerr LoadError
+	var loaded []string
+	for _, path := range files {
+		lfiles, err := loadFile(k, path, origins)
+		if err != nil {
+			lerr.Errors = append(lerr.Errors, err)
+			if mode == FailFast {
+				return &lerr
+			}
+			continue
+		}
+		loaded = append(loaded, lfiles...)
+	}
+	loadOverrides(k, c.overrides, origins) // values set by Set(…) keep precedence
+	c.k, c.origins = k, origins
+	c.files = append(c.files, loaded...)
+	if len(lerr.Errors) > 0 {
+		return &lerr
+	}
+	return nil
+}
+
+// SetSearchPath sets the locations to search for configuration files, e.g.
+//
+//	conf.SetSearchPath(schuko.SystemSearchPath())
+//
+// to include /etc/<tag> and other system-wide locations. sp is used by
+// LoadDefaultFiles, Reload, Watch and Save. If sp is nil, the locations of
+// schuko.LocateConfig are used.
+func (c *KConf) SetSearchPath(sp *schuko.SearchPath) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.search = sp
+}
+
+// searchPath returns the search path for configuration files (see SetSearchPath).
+func (c *KConf) searchPath() *schuko.SearchPath {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	if c.search == nil {
+		return schuko.DefaultSearchPath()
+	}
+	return c.search
+}
+
+// locate returns the configuration files to load, in merge order.
+func (c *KConf) locate() []string {
+	return schuko.Paths(c.searchPath().Locate(c.tag, "", c.suffixes))
+}
+
+// LoadFile loads a configuration file, merging its values into the current
+// configuration. The file format is determined by the file's extension;
+// a parser has to be registered for it (see RegisterParser).
+// If the file fails to load, a *FileError is returned. Files loaded are
+// re-read by Reload.
+func (c *KConf) LoadFile(path string) error {
+	c.loadMx.Lock()
+	defer c.loadMx.Unlock()
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	k, origins := c.k.Copy(), copyOrigins(c.origins)
+	lfiles, err := loadFile(k, path, origins)
+	if err != nil {
+		return err
+	}
+	loadOverrides(k, c.overrides, origins) // values set by Set(…) keep precedence
+	c.k, c.origins = k, origins
+	c.files = append(c.files, lfiles...)
+	c.explicit = append(c.explicit, path)
+	return nil
+}
+
+// loadDefaults loads the built-in default values into k.
+func loadDefaults(k *koanf.Koanf, origins map[string]schuko.Origin) {
+	defaults := map[string]any{
+		"tracing.adapter": "go",
+	}
+	k.Load(confmap.Provider(defaults, k.Delim()), nil)
+	for key := range defaults {
+		origins[key] = schuko.Origin{Kind: schuko.SourceDefault}
+	}
+}
+
+// loadOverrides loads values set by calls to Set into k.
+func loadOverrides(k *koanf.Koanf, overrides map[string]any, origins map[string]schuko.Origin) {
+	for key, v := range overrides {
+		k.Load(confmap.Provider(map[string]any{key: v}, k.Delim()), nil)
+		origins[key] = schuko.Origin{Kind: schuko.SourceSet}
+	}
+}
+
+// IncludeKey is the configuration key of the include directive. Its value is
+// a path or a list of paths of configuration files to include, e.g. in NestedText
+//
+//	include:
+//	    - common.nt
+//	    - conf.d/*.nt
+//
+// Relative paths are resolved against the directory of the including file;
+// glob patterns are expanded in lexical order. Included files are merged
+// first, in the order given, and the including file overrides their values.
+// Includes may be nested; cycles are reported as errors.
+//
+// Include directives are honoured for NestedText and YAML files only (see
+// includeFormats). In files of other formats, e.g. JSON or TOML, "include" is
+// an ordinary key.
+const IncludeKey = "include"
+
+// includeFormats are the formats of files which may include other files.
+var includeFormats = []string{"nt", "yaml", "yml"}
+
+// ErrIncludeCycle is reported for configuration files including themselves,
+// directly or indirectly.
+var ErrIncludeCycle = errors.New("include cycle")
+
+// loadFile loads a configuration file into k, selecting a parser by the
+// file's extension, and records the origins of the keys loaded. Files
+// included by the file (see IncludeKey) are loaded as well.
+// Loading a file is atomic: if it fails, k and origins are unchanged.
+// loadFile returns the paths of all files loaded, including path.
+func loadFile(k *koanf.Koanf, path string, origins map[string]schuko.Origin) ([]string, *FileError) {
+	fk := koanf.New(k.Delim())
+	forigins := make(map[string]schuko.Origin)
+	files, ferr := loadIncluding(fk, path, forigins, nil)
+	if ferr != nil {
+		return nil, ferr
+	}
+	if err := k.Merge(fk); err != nil {
+		return nil, &FileError{Path: path, Format: normalizeExt(filepath.Ext(path)), Err: err}
+	}
+	for key, o := range forigins {
+		origins[key] = o
+	}
+	return files, nil
+}
+
+// loadIncluding loads path and the files it includes into k. stack holds the
+// files currently being loaded, for detecting cycles.
+func loadIncluding(k *koanf.Koanf, path string, origins map[string]schuko.Origin,
+	stack []string) ([]string, *FileError) {
+	//
+	ext := filepath.Ext(path)
+	ferr := &FileError{Path: path, Format: normalizeExt(ext)}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		ferr.Err = err
+		return nil, ferr
+	}
+	if slices.Contains(stack, abs) {
+		ferr.Err = fmt.Errorf("%w: %s", ErrIncludeCycle, strings.Join(append(stack, abs), " → "))
+		return nil, ferr
+	}
+	b, err := os.ReadFile(path) // report missing files before unknown formats
+	if err != nil {
+		ferr.Err = err
+		return nil, ferr
+	}
+	parser, ok := ParserFor(ext)
+	if !ok {
+		ferr.Err = fmt.Errorf("%w %q-files", ErrUnknownFormat, ext)
+		return nil, ferr
+	}
+	fk := koanf.New(k.Delim())
+	if err := fk.Load(rawbytes.Provider(b), parser); err != nil {
+		var nterr nestext.NestedTextError
+		if errors.As(err, &nterr) {
+			ferr.Line, ferr.Column = nterr.Line, nterr.Column
+		}
+		ferr.Err = err
+		return nil, ferr
+	}
+	var includes []string
+	if slices.Contains(includeFormats, ferr.Format) {
+		if includes, err = includesOf(fk, filepath.Dir(path)); err != nil {
+			ferr.Err = err
+			return nil, ferr
+		}
+		fk.Delete(IncludeKey)
+	}
+	var files []string
+	for _, inc := range includes {
+		incFiles, ierr := loadIncluding(k, inc, origins, append(stack, abs))
+		if ierr != nil {
+			return nil, ierr
+		}
+		files = append(files, incFiles...)
+	}
+	if err := k.Merge(fk); err != nil {
+		ferr.Err = err
+		return nil, ferr
+	}
+	lines := keyLines(b, normalizeExt(ext), k.Delim())
+	for key := range fk.All() {
+		origins[key] = schuko.Origin{Kind: schuko.SourceFile, Name: path, Line: lines[key]}
+	}
+	return append(files, path), nil
+}
+
+// includesOf returns the paths of files included by a configuration, resolved
+// against directory dir.
+func includesOf(fk *koanf.Koanf, dir string) ([]string, error) {
+	var refs []string
+	switch v := fk.Get(IncludeKey).(type) {
+	case nil:
+		return nil, nil
+	case string:
+		refs = []string{v}
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: expected path, got %v", IncludeKey, item)
+			}
+			refs = append(refs, s)
+		}
+	default:
+		return nil, fmt.Errorf("%s: expected path or list of paths, got %v", IncludeKey, v)
+	}
+	var paths []string
+	for _, ref := range refs {
+		if !filepath.IsAbs(ref) {
+			ref = filepath.Join(dir, ref)
+		}
+		if !strings.ContainsAny(ref, "*?[") {
+			paths = append(paths, ref)
+			continue
+		}
+		matches, err := filepath.Glob(ref)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", IncludeKey, err)
+		}
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
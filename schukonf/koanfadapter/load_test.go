@@ -0,0 +1,196 @@
+package koanfadapter_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/koanfadapter"
+)
+
+func TestInitReportsFileErrors(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "name: test\nbroken\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	err := c.Init(koanfadapter.FailFast)
+	var lerr *koanfadapter.LoadError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("expected a LoadError, got %v", err)
+	}
+	if len(lerr.Errors) != 1 {
+		t.Fatalf("expected 1 file error, got %d", len(lerr.Errors))
+	}
+	ferr := lerr.Errors[0]
+	t.Logf("error = %v", ferr)
+	if ferr.Path != path || ferr.Format != "nt" {
+		t.Errorf("expected error for %q (nt), got %q (%s)", path, ferr.Path, ferr.Format)
+	}
+	if ferr.Line != 2 {
+		t.Errorf("expected error to be reported at line 2, got line %d", ferr.Line)
+	}
+	if a := c.GetString("tracing.adapter"); a != "go" {
+		t.Errorf("expected defaults to be loaded, got tracing.adapter = %q", a)
+	}
+}
+
+func TestLoadDefaultFilesBestEffort(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "name: test\n")
+	bad := filepath.Join(filepath.Dir(path), "watchtest.json")
+	writeFile(t, bad, `{"name": `)
+	unknown := filepath.Join(filepath.Dir(path), "watchtest.ini")
+	writeFile(t, unknown, "name = ini\n")
+	//
+	c := koanfadapter.New(nil, "watchtest", []string{"nt", "json", "ini"})
+	err := c.LoadDefaultFiles(koanfadapter.FailFast)
+	if err == nil {
+		t.Fatal("expected fail-fast loading to fail")
+	}
+	if c.IsSet("name") {
+		t.Errorf("expected fail-fast loading to leave configuration unchanged")
+	}
+	err = c.LoadDefaultFiles(koanfadapter.BestEffort)
+	var lerr *koanfadapter.LoadError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("expected a LoadError, got %v", err)
+	}
+	if len(lerr.Errors) != 2 {
+		t.Errorf("expected 2 file errors, got %v", err)
+	}
+	if !errors.Is(err, koanfadapter.ErrUnknownFormat) {
+		t.Errorf("expected error for unknown format, got %v", err)
+	}
+	if n := c.GetString("name"); n != "test" {
+		t.Errorf("expected name = test, got %q", n)
+	}
+}
+
+func TestIncludes(t *testing.T) {
+	path := setupConfigDir(t)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(filepath.Join(dir, "parts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, "include:\n    - common.nt\n    - parts/*.nt\nname: main\n")
+	writeFile(t, filepath.Join(dir, "common.nt"), "include: base.nt\nname: common\ndb:\n    host: common\n")
+	writeFile(t, filepath.Join(dir, "base.nt"), "db:\n    port: 5432\n    host: base\n")
+	writeFile(t, filepath.Join(dir, "parts", "20-b.nt"), "db:\n    user: b\n")
+	writeFile(t, filepath.Join(dir, "parts", "10-a.nt"), "db:\n    user: a\n    pool: 5\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	if err := c.Init(koanfadapter.FailFast); err != nil {
+		t.Fatal(err)
+	}
+	for key, expected := range map[string]string{
+		"name":    "main",   // including file overrides included files
+		"db.host": "common", // nested include is overridden by its includer
+		"db.port": "5432",
+		"db.user": "b", // globs expand in lexical order
+		"db.pool": "5",
+	} {
+		if v := c.GetString(key); v != expected {
+			t.Errorf("expected %s = %q, got %q", key, expected, v)
+		}
+	}
+	if c.IsSet(koanfadapter.IncludeKey) {
+		t.Errorf("expected include directive not to be part of the configuration")
+	}
+	if o, _ := c.Origin("db.port"); o.Name != filepath.Join(dir, "base.nt") || o.Line != 2 {
+		t.Errorf("expected db.port to originate from base.nt:2, got %v", o)
+	}
+}
+
+func TestIncludeFormats(t *testing.T) {
+	path := filepath.Join(filepath.Dir(setupConfigDir(t)), "config.json")
+	dir := filepath.Dir(path)
+	writeFile(t, path, `{"include": "common.nt", "name": "main"}`)
+	writeFile(t, filepath.Join(dir, "common.nt"), "db:\n    host: common\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"json"})
+	if err := c.Init(koanfadapter.FailFast); err != nil {
+		t.Fatal(err)
+	}
+	if inc := c.GetString(koanfadapter.IncludeKey); inc != "common.nt" {
+		t.Errorf("expected include of JSON file to be an ordinary key, got %q", inc)
+	}
+	if c.IsSet("db.host") {
+		t.Errorf("expected JSON file not to include other files")
+	}
+}
+
+func TestIncludeCycle(t *testing.T) {
+	path := setupConfigDir(t)
+	dir := filepath.Dir(path)
+	writeFile(t, path, "include: a.nt\nname: main\n")
+	writeFile(t, filepath.Join(dir, "a.nt"), "include: b.nt\n")
+	writeFile(t, filepath.Join(dir, "b.nt"), "include: a.nt\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	err := c.Init(koanfadapter.FailFast)
+	if !errors.Is(err, koanfadapter.ErrIncludeCycle) {
+		t.Fatalf("expected include cycle to be reported, got %v", err)
+	}
+	t.Logf("error = %v", err)
+	if c.IsSet("name") {
+		t.Errorf("expected configuration to be unchanged after failed include")
+	}
+}
+
+func TestDropInFiles(t *testing.T) {
+	path := setupConfigDir(t)
+	dropins := filepath.Join(filepath.Dir(path), schuko.DropInDir)
+	if err := os.MkdirAll(dropins, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, "name: main\nport: 80\nuser: root\n")
+	writeFile(t, filepath.Join(dropins, "20-port.nt"), "port: 8080\n")
+	writeFile(t, filepath.Join(dropins, "10-port.nt"), "port: 8000\nuser: app\n")
+	writeFile(t, filepath.Join(dropins, ".hidden.nt"), "name: hidden\n")
+	files := schuko.LocateConfig("watchtest", "", []string{"nt"})
+	expected := []string{path, filepath.Join(dropins, "10-port.nt"), filepath.Join(dropins, "20-port.nt")}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected files in merge order %v, got %v", expected, files)
+	}
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.InitDefaults()
+	if p, u, n := c.GetInt("port"), c.GetString("user"), c.GetString("name"); p != 8080 || u != "app" || n != "main" {
+		t.Errorf("expected drop-ins merged in lexical order, got port=%d, user=%q, name=%q", p, u, n)
+	}
+}
+
+func TestSetSearchPath(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "name: user\nport: 80\n")
+	sys := t.TempDir()
+	t.Setenv("XDG_CONFIG_DIRS", sys)
+	if err := os.MkdirAll(filepath.Join(sys, "watchtest"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(sys, "watchtest", "config.nt"), "name: system\nuser: daemon\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.SetSearchPath(schuko.NewSearchPath(schuko.CollectAll(),
+		schuko.WithRules(schuko.XDGConfigHomeRule(), schuko.XDGConfigDirsRule())))
+	if err := c.Init(koanfadapter.FailFast); err != nil {
+		t.Fatal(err)
+	}
+	if n, u := c.GetString("name"), c.GetString("user"); n != "user" || u != "daemon" {
+		t.Errorf("expected user configuration to override system configuration, got name=%q, user=%q", n, u)
+	}
+}
+
+func TestMissingEnvOverride(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "name: user\n")
+	missing := filepath.Join(t.TempDir(), "missing.nt")
+	t.Setenv(schuko.ConfigEnvName("watchtest"), missing)
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.SetSearchPath(schuko.SystemSearchPath())
+	err := c.Init(koanfadapter.BestEffort)
+	var ferr *koanfadapter.FileError
+	if !errors.As(err, &ferr) || ferr.Path != missing || !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected missing file %q to be reported, got %v", missing, err)
+	}
+	if n := c.GetString("name"); n != "user" {
+		t.Errorf("expected other files to be loaded, got name=%q", n)
+	}
+}
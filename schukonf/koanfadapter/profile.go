@@ -0,0 +1,95 @@
+package koanfadapter
+
+import (
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/npillmayer/schuko"
+)
+
+// LoadProfile overlays the configuration with the settings of a profile, e.g.
+// "prod". If profile is empty, the active profile is used (see
+// schuko.ActiveProfile). Settings are applied in order of increasing precedence:
+//
+//  1. the defaults of the profile (see schuko.RegisterProfile), replacing
+//     built-in defaults only
+//  2. the profile section of the configuration, i.e. keys "profiles.<profile>.*"
+//  3. profile-specific files, e.g. “myapp.prod.nt”, searched for at the same
+//     locations as other configuration files (see SetSearchPath)
+//
+// Values set by Set(…) keep precedence. The profile is remembered and re-applied
+// by Reload. Errors for files are reported as for LoadDefaultFiles.
+func (c *KConf) LoadProfile(profile string, mode LoadMode) error {
+	if profile == "" {
+		profile = schuko.ActiveProfile(c.tag)
+	}
+	if profile == "" {
+		return nil
+	}
+	c.loadMx.Lock()
+	defer c.loadMx.Unlock()
+	sp := c.searchPath()
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	k, origins := c.k.Copy(), copyOrigins(c.origins)
+	loaded, lerr := loadProfile(k, origins, sp, c.tag, profile, c.suffixes, mode)
+	if lerr != nil && mode == FailFast {
+		return lerr
+	}
+	loadOverrides(k, c.overrides, origins) // values set by Set(…) keep precedence
+	c.k, c.origins = k, origins
+	c.profile = profile
+	c.files = append(c.files, loaded...)
+	if lerr != nil {
+		return lerr
+	}
+	return nil
+}
+
+// Profile returns the profile applied by LoadProfile, if any.
+func (c *KConf) Profile() string {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	return c.profile
+}
+
+// loadProfile applies the settings of profile to k, recording origins.
+// Profile-specific files are searched for at the locations of sp.
+// It returns the profile-specific files loaded.
+func loadProfile(k *koanf.Koanf, origins map[string]schuko.Origin, sp *schuko.SearchPath,
+	tag, profile string, suffixes []string, mode LoadMode) ([]string, *LoadError) {
+	//
+	defaults := make(map[string]any)
+	for key, v := range schuko.ProfileDefaults(tag, profile) {
+		if o, found := origins[key]; !k.Exists(key) || (found && o.Kind == schuko.SourceDefault) {
+			defaults[key] = v
+			origins[key] = schuko.Origin{Kind: schuko.SourceDefault, Name: "profile " + profile}
+		}
+	}
+	k.Load(confmap.Provider(defaults, k.Delim()), nil)
+	section := schuko.ProfileSection + k.Delim() + profile
+	if sk := k.Cut(section); len(sk.Keys()) > 0 {
+		k.Merge(sk)
+		for _, key := range sk.Keys() {
+			if o, found := origins[section+k.Delim()+key]; found {
+				origins[key] = o
+			}
+		}
+	}
+	var lerr LoadError
+	var loaded []string
+	for _, path := range schuko.Paths(sp.ProfileFiles(tag, profile, suffixes)) {
+		lfiles, err := loadFile(k, path, origins)
+		if err != nil {
+			lerr.Errors = append(lerr.Errors, err)
+			if mode == FailFast {
+				return nil, &lerr
+			}
+			continue
+		}
+		loaded = append(loaded, lfiles...)
+	}
+	if len(lerr.Errors) > 0 {
+		return loaded, &lerr
+	}
+	return loaded, nil
+}
@@ -0,0 +1,76 @@
+package koanfadapter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/koanfadapter"
+)
+
+func TestLoadProfile(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "name: app\nprofiles:\n    prod:\n        name: app-prod\n        port: 80\n")
+	prodFile := filepath.Join(filepath.Dir(path), "watchtest.prod.nt")
+	writeFile(t, prodFile, "port: 8080\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.InitDefaults()
+	if n := c.GetString("name"); n != "app" {
+		t.Fatalf("expected name = app before loading profile, got %q", n)
+	}
+	if err := c.LoadProfile(schuko.ProfileProduction, koanfadapter.FailFast); err != nil {
+		t.Fatal(err)
+	}
+	if p := c.Profile(); p != schuko.ProfileProduction {
+		t.Errorf("expected profile to be remembered, got %q", p)
+	}
+	if n := c.GetString("name"); n != "app-prod" {
+		t.Errorf("expected name from profile section, got %q", n)
+	}
+	if p := c.GetInt("port"); p != 8080 {
+		t.Errorf("expected port from profile file, got %d", p)
+	}
+	if a := c.GetString("tracing.adapter"); a != "slogjson" {
+		t.Errorf("expected built-in default to be replaced by profile default, got %q", a)
+	}
+	if o, _ := c.Origin("port"); o.Kind != schuko.SourceFile || o.Name != prodFile {
+		t.Errorf("expected port to originate from profile file, got %v", o)
+	}
+	writeFile(t, prodFile, "port: 9090\n")
+	if _, err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if p := c.GetInt("port"); p != 9090 {
+		t.Errorf("expected profile to be re-applied on reload, got port %d", p)
+	}
+}
+
+func TestLoadProfileWithSearchPath(t *testing.T) {
+	setupConfigDir(t) // user directory exists, without files
+	sys := t.TempDir()
+	t.Setenv("XDG_CONFIG_DIRS", sys)
+	dir := filepath.Join(sys, "watchtest")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "config.nt"), "port: 80\n")
+	writeFile(t, filepath.Join(dir, "watchtest.prod.nt"), "port: 8080\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.SetSearchPath(schuko.SystemSearchPath())
+	if err := c.Init(koanfadapter.FailFast); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadProfile(schuko.ProfileProduction, koanfadapter.FailFast); err != nil {
+		t.Fatal(err)
+	}
+	if p := c.GetInt("port"); p != 8080 {
+		t.Errorf("expected port from profile file of search path, got %d", p)
+	}
+	if _, err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if p := c.GetInt("port"); p != 8080 {
+		t.Errorf("expected profile file of search path to be re-applied on reload, got port %d", p)
+	}
+}
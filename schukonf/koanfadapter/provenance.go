@@ -0,0 +1,164 @@
+package koanfadapter
+
+import (
+	"io"
+	"strings"
+
+	"github.com/npillmayer/schuko"
+)
+
+// Origin returns the origin of the value for key: a built-in default, a
+// configuration file (with line number for NestedText, YAML and TOML files,
+// and for JSON files with one key per line) or a call to Set.
+func (c *KConf) Origin(key string) (schuko.Origin, bool) {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	if !c.k.Exists(key) {
+		return schuko.Origin{}, false
+	}
+	return c.originOf(key), true
+}
+
+// Origins returns the origins of all keys of the configuration.
+func (c *KConf) Origins() map[string]schuko.Origin {
+	c.mx.RLock()
+	defer c.mx.RUnlock()
+	all := c.k.All()
+	origins := make(map[string]schuko.Origin, len(all))
+	for key := range all {
+		origins[key] = c.originOf(key)
+	}
+	return origins
+}
+
+// originOf looks up the origin of key. Keys without an origin of their own
+// inherit the origin of their closest ancestor, e.g. for a dictionary passed
+// to Set. Has to be called with c.mx held.
+func (c *KConf) originOf(key string) schuko.Origin {
+	delim := c.k.Delim()
+	for k := key; ; {
+		if o, ok := c.origins[k]; ok {
+			return o
+		}
+		i := strings.LastIndex(k, delim)
+		if i < 0 {
+			return schuko.Origin{}
+		}
+		k = k[:i]
+	}
+}
+
+// WriteExplanations writes explanations of configuration values (see
+// schuko.Explain) in NestedText format.
+func WriteExplanations(w io.Writer, exps []schuko.Explanation) error {
+	b, err := Parser().Marshal(schuko.ExplanationMap(exps))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func copyOrigins(origins map[string]schuko.Origin) map[string]schuko.Origin {
+	c := make(map[string]schuko.Origin, len(origins))
+	for k, o := range origins {
+		c[k] = o
+	}
+	return c
+}
+
+// keyLines makes a best effort to find the line numbers of keys in the content
+// of a configuration file of the given format (see normalizeExt). It understands
+// TOML and formats structured by indentation, i.e. NestedText, YAML and JSON with
+// one key per line. For compact JSON and formats of parsers registered by
+// clients, no line numbers are found. Keys of nested dictionaries are joined
+// with delim.
+func keyLines(b []byte, format string, delim string) map[string]int {
+	switch format {
+	case "toml", "tml":
+		return tomlKeyLines(b, delim)
+	case "nt", "json", "jsn", "yaml", "yml":
+		return indentedKeyLines(b, delim)
+	}
+	return nil
+}
+
+// indentedKeyLines finds the line numbers of keys for formats structured by
+// indentation.
+func indentedKeyLines(b []byte, delim string) map[string]int {
+	type level struct {
+		indent int
+		key    string
+	}
+	lines := make(map[string]int)
+	var stack []level
+	for i, line := range strings.Split(string(b), "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || strings.ContainsAny(trimmed[:1], "#-[{}]>") {
+			continue
+		}
+		key, _, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		indent := len(line) - len(trimmed)
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) > 0 {
+			key = stack[len(stack)-1].key + delim + key
+		}
+		stack = append(stack, level{indent: indent, key: key})
+		if _, seen := lines[key]; !seen {
+			lines[key] = i + 1
+		}
+	}
+	return lines
+}
+
+// tomlKeyLines finds the line numbers of keys in TOML content, i.e. of
+// “key = value” pairs below the most recent table header.
+func tomlKeyLines(b []byte, delim string) map[string]int {
+	lines := make(map[string]int)
+	var table string
+	multiline := false
+	for i, line := range strings.Split(string(b), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if multiline {
+			multiline = strings.Count(trimmed, `"""`)%2 == 0 && strings.Count(trimmed, "'''")%2 == 0
+			continue
+		}
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+		if trimmed[0] == '[' {
+			header, _, _ := strings.Cut(strings.Trim(trimmed, "[ "), "]")
+			table = tomlKey(header, delim)
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			continue
+		}
+		multiline = strings.Count(value, `"""`)%2 == 1 || strings.Count(value, "'''")%2 == 1
+		key = tomlKey(key, delim)
+		if table != "" {
+			key = table + delim + key
+		}
+		if _, seen := lines[key]; !seen {
+			lines[key] = i + 1
+		}
+	}
+	return lines
+}
+
+// tomlKey converts a (possibly dotted and quoted) TOML key to a key joined
+// with delim.
+func tomlKey(key string, delim string) string {
+	parts := strings.Split(key, ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return strings.Join(parts, delim)
+}
@@ -0,0 +1,82 @@
+package koanfadapter_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/koanfadapter"
+)
+
+func TestOriginOfValues(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "# app config\nname: test\ndb:\n    host: db.local\n    port: 5432\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.InitDefaults()
+	c.Set("db.port", 6543)
+	tests := []struct {
+		key  string
+		kind schuko.SourceKind
+		line int
+	}{
+		{"tracing.adapter", schuko.SourceDefault, 0},
+		{"name", schuko.SourceFile, 2},
+		{"db.host", schuko.SourceFile, 4},
+		{"db.port", schuko.SourceSet, 0},
+	}
+	for _, tt := range tests {
+		o, found := c.Origin(tt.key)
+		if !found || o.Kind != tt.kind || o.Line != tt.line {
+			t.Errorf("expected %s from %s at line %d, got %v", tt.key, tt.kind, tt.line, o)
+		}
+	}
+	if o, _ := c.Origin("db.host"); o.Name != path {
+		t.Errorf("expected db.host to originate from %q, got %q", path, o.Name)
+	}
+	if _, found := c.Origin("nope"); found {
+		t.Errorf("expected no origin for unset key")
+	}
+	if e := schuko.Explain(c, "db.host"); e.Value != "db.local" || e.Origin.Kind != schuko.SourceFile {
+		t.Errorf("unexpected explanation %v", e)
+	}
+}
+
+func TestWriteExplanations(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "name: test\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.InitDefaults()
+	exps := schuko.ExplainAll(c)
+	if len(exps) != 2 {
+		t.Fatalf("expected 2 explanations, got %v", exps)
+	}
+	var buf bytes.Buffer
+	if err := koanfadapter.WriteExplanations(&buf, exps); err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("explanations:\n%s", buf.String())
+	if !strings.Contains(buf.String(), path) {
+		t.Errorf("expected dump to mention %q", path)
+	}
+	m, err := koanfadapter.Parser().Unmarshal(buf.Bytes())
+	if err != nil {
+		t.Fatalf("expected dump to be valid NestedText: %v", err)
+	}
+	if entry, ok := m["name"].(map[string]any); !ok || entry["source"] != "file" || entry["line"] != "1" {
+		t.Errorf("unexpected entry for name: %v", m["name"])
+	}
+}
+
+func TestOriginLinesTOML(t *testing.T) {
+	path := filepath.Join(filepath.Dir(setupConfigDir(t)), "config.toml")
+	writeFile(t, path, "name = \"test\"\nnote = \"\"\"\nx = 1\n\"\"\"\n\n[db]\n# primary\nhost = \"db.local\"\npool.size = 10\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"toml"})
+	c.InitDefaults()
+	for key, line := range map[string]int{"name": 1, "db.host": 8, "db.pool.size": 9} {
+		if o, _ := c.Origin(key); o.Kind != schuko.SourceFile || o.Line != line {
+			t.Errorf("expected %s from line %d, got %v", key, line, o)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package koanfadapter
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/parsers/toml"
+	"github.com/knadh/koanf/parsers/yaml"
+)
+
+// parsers is a registry of koanf parsers, keyed by file extension.
+var parsers = map[string]koanf.Parser{
+	"nt":   Parser(),
+	"json": json.Parser(),
+	"jsn":  json.Parser(),
+	"yaml": yaml.Parser(),
+	"yml":  yaml.Parser(),
+	"toml": toml.Parser(),
+	"tml":  toml.Parser(),
+}
+var parserMutex = &sync.RWMutex{} // guard parsers[]
+
+// RegisterParser is an extension point for clients who want to use configuration
+// file formats other than the bundled ones. Bundled are parsers for NestedText
+// (".nt"), JSON (".json", ".jsn"), YAML (".yaml", ".yml") and TOML (".toml", ".tml").
+//
+// ext is a file extension, with or without a leading dot; case is ignored.
+// A parser already registered for ext will be replaced.
+func RegisterParser(ext string, parser koanf.Parser) {
+	parserMutex.Lock()
+	defer parserMutex.Unlock()
+	parsers[normalizeExt(ext)] = parser
+}
+
+// ParserFor returns the parser registered for a file extension, if any.
+func ParserFor(ext string) (koanf.Parser, bool) {
+	parserMutex.RLock()
+	defer parserMutex.RUnlock()
+	p, ok := parsers[normalizeExt(ext)]
+	return p, ok
+}
+
+// Extensions returns the file extensions for which a parser is registered,
+// without leading dots and in sorted order. The result is suited as argument
+// `suffixes` for New.
+func Extensions() []string {
+	parserMutex.RLock()
+	defer parserMutex.RUnlock()
+	exts := make([]string, 0, len(parsers))
+	for ext := range parsers {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+func normalizeExt(ext string) string {
+	return strings.ToLower(strings.TrimPrefix(ext, "."))
+}
@@ -0,0 +1,63 @@
+package koanfadapter_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/schuko/schukonf/koanfadapter"
+)
+
+func TestLoadFileFormats(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"app.json": `{"server": {"port": 8080}}`,
+		"app.yaml": "server:\n  port: 8080\n",
+		"app.toml": "[server]\nport = 8080\n",
+		"app.nt":   "server:\n    port: 8080\n",
+	} {
+		path := filepath.Join(dir, name)
+		writeFile(t, path, content)
+		c := koanfadapter.New(nil, "", nil)
+		if err := c.LoadFile(path); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if p := c.GetInt("server.port"); p != 8080 {
+			t.Errorf("%s: expected server.port = 8080, got %d", name, p)
+		}
+	}
+}
+
+type kvParser struct{}
+
+func (kvParser) Unmarshal(b []byte) (map[string]any, error) {
+	m := make(map[string]any)
+	for _, line := range strings.Split(string(b), "\n") {
+		if k, v, ok := strings.Cut(line, "="); ok {
+			m[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return m, nil
+}
+
+func (kvParser) Marshal(map[string]any) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestRegisterParser(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.kv")
+	writeFile(t, path, "name = test\n")
+	c := koanfadapter.New(nil, "", nil)
+	if err := c.LoadFile(path); err == nil {
+		t.Fatalf("expected error for unknown extension .kv")
+	}
+	koanfadapter.RegisterParser(".KV", kvParser{})
+	if err := c.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if n := c.GetString("name"); n != "test" {
+		t.Errorf("expected name = test, got %q", n)
+	}
+}
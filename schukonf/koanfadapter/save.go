@@ -0,0 +1,77 @@
+package koanfadapter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/npillmayer/schuko"
+)
+
+// Save writes the values set by calls to Set back to the configuration file
+// of the application (see schuko.SaveTarget), i.e. to the main file found at
+// the locations searched (see SetSearchPath), or to a new file in the
+// application's directory below os.UserConfigDir. It returns the path of the
+// file written.
+//
+// Save is a shortcut for SaveTo(…) with the path of the file determined by
+// schuko.SearchPath.SaveTarget; see SaveTo for details.
+func (c *KConf) Save() (string, error) {
+	path, err := c.searchPath().SaveTarget(c.tag, c.suffixes)
+	if err != nil {
+		return "", err
+	}
+	return path, c.SaveTo(path)
+}
+
+// SaveTo writes the values set by calls to Set to a configuration file.
+// Values of an existing file are preserved, i.e. values set are merged into
+// them, and the file keeps its format. Values of other sources, e.g. other
+// files or defaults, are not written. The format of a new file is determined
+// by its extension (see RegisterParser). Directories are created as needed,
+// and the file is replaced atomically (see schuko.WriteFileAtomic).
+//
+// Errors are reported as a *FileError.
+func (c *KConf) SaveTo(path string) error {
+	ext := filepath.Ext(path)
+	ferr := &FileError{Path: path, Format: normalizeExt(ext)}
+	parser, ok := ParserFor(ext)
+	if !ok {
+		ferr.Err = fmt.Errorf("%w %q-files", ErrUnknownFormat, ext)
+		return ferr
+	}
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	fk := koanf.New(c.k.Delim())
+	b, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		ferr.Err = err
+		return ferr
+	}
+	if len(b) > 0 {
+		if err := fk.Load(rawbytes.Provider(b), parser); err != nil {
+			ferr.Err = err
+			return ferr
+		}
+	}
+	if err := fk.Load(confmap.Provider(c.overrides, fk.Delim()), nil); err != nil {
+		ferr.Err = err
+		return ferr
+	}
+	out, err := parser.Marshal(fk.Raw())
+	if err != nil {
+		ferr.Err = err
+		return ferr
+	}
+	if err := schuko.WriteFileAtomic(path, out, 0o644); err != nil {
+		ferr.Err = err
+		return ferr
+	}
+	return nil
+}
+
+var _ schuko.PersistentConfiguration = &KConf{}
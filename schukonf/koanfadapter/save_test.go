@@ -0,0 +1,93 @@
+package koanfadapter_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/koanfadapter"
+)
+
+func TestSaveToLocatedFile(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "include: common.nt\nname: test\nui:\n    theme: light\n")
+	writeFile(t, filepath.Join(filepath.Dir(path), "common.nt"), "db:\n    host: db.local\n")
+	if err := os.Chmod(path, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.InitDefaults()
+	c.Set("ui.theme", "dark")
+	saved, err := c.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved != path {
+		t.Errorf("expected configuration to be saved to %q, got %q", path, saved)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(b)
+	t.Logf("saved:\n%s", out)
+	for _, s := range []string{"theme: dark", "name: test", "include: common.nt"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("expected %q in saved file", s)
+		}
+	}
+	if strings.Contains(out, "db.local") || strings.Contains(out, "tracing") {
+		t.Errorf("expected values of other sources not to be saved")
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("expected permissions of file to be kept, got %v", fi.Mode())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 2 {
+		t.Errorf("expected no temporary files to be left, got %v", entries)
+	}
+}
+
+func TestSaveToNewFile(t *testing.T) {
+	path := setupConfigDir(t) // directory is set up, file does not exist
+	os.RemoveAll(filepath.Dir(path))
+	c := koanfadapter.New(nil, "watchtest", []string{"yaml", "nt"})
+	c.InitDefaults()
+	c.Set("ui.theme", "dark")
+	saved, err := c.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := filepath.Join(filepath.Dir(path), "config.yaml"); saved != expected {
+		t.Errorf("expected new file %q, got %q", expected, saved)
+	}
+	c = koanfadapter.New(nil, "watchtest", []string{"yaml", "nt"})
+	c.InitDefaults()
+	if th := c.GetString("ui.theme"); th != "dark" {
+		t.Errorf("expected saved value to be loaded, got %q", th)
+	}
+}
+
+func TestSaveWithSearchPath(t *testing.T) {
+	setupConfigDir(t) // user directory exists, without files
+	sys := t.TempDir()
+	t.Setenv("XDG_CONFIG_DIRS", sys)
+	path := filepath.Join(sys, "watchtest", "config.nt")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, path, "name: system\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.SetSearchPath(schuko.SystemSearchPath())
+	c.InitDefaults()
+	c.Set("name", "changed")
+	saved, err := c.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved != path {
+		t.Errorf("expected configuration to be saved to %q, got %q", path, saved)
+	}
+}
@@ -0,0 +1,306 @@
+package koanfadapter
+
+import (
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/knadh/koanf"
+	"github.com/npillmayer/schuko"
+)
+
+// Reload re-reads the configuration files found at the “natural” configuration
+// locations (see InitFromDefaultFile) and replaces the current configuration
+// with a fresh one. The new configuration consists of built-in defaults, the
+// files located, files loaded by LoadFile (in the order loaded), the settings
+// of a profile applied by LoadProfile, and values set by calls to Set. Values
+// loaded into the Koanf instance by other means will be lost.
+//
+// Reload returns the keys which have been added, removed or changed, in sorted
+// order. Subscribers registered for any of these keys will be notified.
+// Concurrent calls to Reload and to the Load…-methods are serialized; values
+// set while reloading are part of the new configuration.
+//
+// Files of unknown format are reported to the Go standard logger and skipped,
+// as with InitFromDefaultFile. If loading fails, the previous configuration is
+// kept intact and an error is returned.
+func (c *KConf) Reload() ([]string, error) {
+	c.loadMx.Lock()
+	defer c.loadMx.Unlock()
+	c.mx.RLock()
+	k := koanf.New(c.k.Delim())
+	explicit := slices.Clone(c.explicit)
+	profile := c.profile
+	c.mx.RUnlock()
+	origins := make(map[string]schuko.Origin)
+	loadDefaults(k, origins)
+	var files []string
+	if c.tag != "" {
+		files = c.locate()
+	}
+	files = append(files, explicit...)
+	loaded := make([]string, 0, len(files))
+	for _, path := range files {
+		lfiles, err := loadFile(k, path, origins)
+		if err != nil {
+			if errors.Is(err, ErrUnknownFormat) {
+				log.Printf("skipping configuration file: %v", err)
+				continue
+			}
+			return nil, &LoadError{Errors: []*FileError{err}}
+		}
+		loaded = append(loaded, lfiles...)
+	}
+	if profile != "" {
+		pfiles, lerr := loadProfile(k, origins, c.searchPath(), c.tag, profile, c.suffixes, FailFast)
+		if lerr != nil {
+			return nil, lerr
+		}
+		loaded = append(loaded, pfiles...)
+	}
+	c.mx.Lock()
+	loadOverrides(k, c.overrides, origins) // including values set while reloading
+	prev := c.k
+	c.k, c.origins = k, origins
+	c.files = loaded
+	c.mx.Unlock()
+	changed := changedKeys(prev.All(), k.All())
+	c.notify(changed)
+	return changed, nil
+}
+
+// changedKeys compares two flattened configuration maps and returns all keys
+// which are present in only one of them or have different values.
+func changedKeys(prev, next map[string]any) []string {
+	var changed []string
+	for key, v := range next {
+		if pv, ok := prev[key]; !ok || !reflect.DeepEqual(pv, v) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range prev {
+		if _, ok := next[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// --- Subscriptions ---------------------------------------------------------
+
+type subscription struct {
+	prefix string
+	fn     func(changed []string)
+}
+
+// Subscribe registers a function to be called whenever a reload changes keys
+// starting with prefix. An empty prefix subscribes to changes of all keys.
+// fn is called with the changed keys matching prefix.
+//
+// Subscribers are called synchronously, one after the other, before Reload
+// returns. With Watch, this happens on the watching goroutine. fn therefore
+// must not block: a slow subscriber delays all other subscribers and the
+// detection of further modifications. Subscribers with lengthy work to do
+// should hand it off, e.g. to a goroutine of their own or by a non-blocking
+// send to a buffered channel.
+//
+// It returns a function to cancel the subscription.
+func (c *KConf) Subscribe(prefix string, fn func(changed []string)) (unsubscribe func()) {
+	sub := &subscription{prefix: prefix, fn: fn}
+	c.subMx.Lock()
+	defer c.subMx.Unlock()
+	c.subs = append(c.subs, sub)
+	return func() {
+		c.subMx.Lock()
+		defer c.subMx.Unlock()
+		for i, s := range c.subs {
+			if s == sub {
+				c.subs = append(c.subs[:i], c.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (c *KConf) notify(changed []string) {
+	if len(changed) == 0 {
+		return
+	}
+	c.subMx.Lock()
+	subs := make([]*subscription, len(c.subs))
+	copy(subs, c.subs)
+	c.subMx.Unlock()
+	delim := c.konf().Delim()
+	for _, sub := range subs {
+		var keys []string
+		for _, key := range changed {
+			if sub.prefix == "" || key == sub.prefix || strings.HasPrefix(key, sub.prefix+delim) {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) > 0 {
+			sub.fn(keys)
+		}
+	}
+}
+
+// --- Watching files --------------------------------------------------------
+
+// WatchOption is a type to influence watching of configuration files.
+// Multiple options may be passed to `Watch(…)`.
+type WatchOption func(*watcher)
+
+// PollInterval sets the interval for checking configuration files for
+// modifications. Default is 5 seconds.
+func PollInterval(d time.Duration) WatchOption {
+	return func(w *watcher) {
+		w.interval = d
+	}
+}
+
+// UseFSNotify switches from polling to file system notifications, using
+// fsnotify. Only directories containing configuration files present at the
+// time of the call to Watch are observed.
+func UseFSNotify() WatchOption {
+	return func(w *watcher) {
+		w.fsnotify = true
+	}
+}
+
+// OnReloadError sets a function to be called if reloading the configuration
+// fails. The previous configuration will stay in effect.
+func OnReloadError(fn func(error)) WatchOption {
+	return func(w *watcher) {
+		w.onError = fn
+	}
+}
+
+type watcher struct {
+	conf     *KConf
+	interval time.Duration
+	fsnotify bool
+	onError  func(error)
+	done     chan struct{}
+}
+
+// Watch starts observing the configuration files of c for modifications.
+// On modification, the configuration is reloaded (see Reload) and subscribers
+// are notified. By default Watch polls the files located by schuko.LocateConfig,
+// detecting modified, new and removed files.
+//
+// Watch returns a function to stop watching.
+func (c *KConf) Watch(opts ...WatchOption) (stop func(), err error) {
+	w := &watcher{
+		conf:     c,
+		interval: 5 * time.Second,
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.fsnotify {
+		err = w.notifyLoop()
+	} else {
+		go w.pollLoop(w.stamps())
+	}
+	if err != nil {
+		return func() {}, err
+	}
+	return func() { close(w.done) }, nil
+}
+
+func (w *watcher) reload() {
+	if _, err := w.conf.Reload(); err != nil && w.onError != nil {
+		w.onError(err)
+	}
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// stamps returns a fingerprint of all configuration files currently located,
+// and of all files loaded, e.g. included files.
+func (w *watcher) stamps() map[string]fileStamp {
+	files := w.conf.locate()
+	w.conf.mx.RLock()
+	files = append(files, w.conf.files...)
+	w.conf.mx.RUnlock()
+	stamps := make(map[string]fileStamp, len(files))
+	for _, path := range files {
+		if fi, err := os.Stat(path); err == nil {
+			stamps[path] = fileStamp{modTime: fi.ModTime(), size: fi.Size()}
+		}
+	}
+	return stamps
+}
+
+func (w *watcher) pollLoop(last map[string]fileStamp) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			if current := w.stamps(); !reflect.DeepEqual(last, current) {
+				last = current
+				w.reload()
+			}
+		}
+	}
+}
+
+func (w *watcher) notifyLoop() error {
+	w.conf.mx.RLock()
+	files := w.conf.files
+	w.conf.mx.RUnlock()
+	if len(files) == 0 {
+		return errors.New("no configuration files to watch")
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	watched := make(map[string]bool)
+	for _, path := range files {
+		watched[filepath.Clean(path)] = true
+		if err := fsw.Add(filepath.Dir(path)); err != nil {
+			fsw.Close()
+			return err
+		}
+	}
+	go func() {
+		defer fsw.Close()
+		for {
+			select {
+			case <-w.done:
+				return
+			case event, ok := <-fsw.Events:
+				if !ok {
+					return
+				}
+				if watched[filepath.Clean(event.Name)] {
+					w.reload()
+				}
+			case err, ok := <-fsw.Errors:
+				if !ok {
+					return
+				}
+				if w.onError != nil {
+					w.onError(err)
+				}
+			}
+		}
+	}()
+	return nil
+}
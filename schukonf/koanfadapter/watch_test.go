@@ -0,0 +1,198 @@
+package koanfadapter_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/npillmayer/schuko/schukonf/koanfadapter"
+)
+
+// setupConfigDir creates a temporary $HOME with a configuration directory for
+// app tag "watchtest" and returns the path of the configuration file.
+func setupConfigDir(t *testing.T) string {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	dir := filepath.Join(home, ".config", "watchtest")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return filepath.Join(dir, "config.nt")
+}
+
+func writeFile(t *testing.T, path, content string) {
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadNotifiesSubscribers(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "tracelevel:\n    root: Info\n    db: Info\nname: test\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.InitDefaults()
+	c.Set("extra", "x")
+	if l := c.GetString("tracelevel.db"); l != "Info" {
+		t.Fatalf("expected tracelevel.db = Info, got %q", l)
+	}
+	var got []string
+	unsubscribe := c.Subscribe("tracelevel", func(changed []string) {
+		got = changed
+	})
+	defer unsubscribe()
+	writeFile(t, path, "tracelevel:\n    root: Info\n    db: Debug\nname: test\n")
+	changed, err := c.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 1 || changed[0] != "tracelevel.db" {
+		t.Errorf("expected tracelevel.db to be the only change, got %v", changed)
+	}
+	if len(got) != 1 || got[0] != "tracelevel.db" {
+		t.Errorf("expected subscriber to be notified of tracelevel.db, got %v", got)
+	}
+	if l := c.GetString("tracelevel.db"); l != "Debug" {
+		t.Errorf("expected tracelevel.db = Debug, got %q", l)
+	}
+	if c.GetString("extra") != "x" {
+		t.Errorf("expected value set by Set to survive reload")
+	}
+}
+
+func TestReloadFailureKeepsConfiguration(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "name: test\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.InitDefaults()
+	writeFile(t, path, "name: test\n  broken: - indentation\n")
+	if _, err := c.Reload(); err == nil {
+		t.Fatalf("expected reload of broken file to fail")
+	}
+	if c.GetString("name") != "test" {
+		t.Errorf("expected previous configuration to be intact")
+	}
+}
+
+func TestWatchPolling(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "name: first\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.InitDefaults()
+	var mx sync.Mutex
+	var errs []error
+	reloaded := make(chan struct{}, 1)
+	c.Subscribe("name", func([]string) {
+		select { // subscribers must not block
+		case reloaded <- struct{}{}:
+		default:
+		}
+	})
+	stop, err := c.Watch(koanfadapter.PollInterval(10*time.Millisecond), koanfadapter.OnReloadError(func(err error) {
+		mx.Lock()
+		defer mx.Unlock()
+		errs = append(errs, err)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	writeFile(t, path, "name: second one\n")
+	select {
+	case <-reloaded:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected configuration to be reloaded")
+	}
+	if n := c.GetString("name"); n != "second one" {
+		t.Errorf("expected name = 'second one', got %q", n)
+	}
+	mx.Lock()
+	defer mx.Unlock()
+	if err := errors.Join(errs...); err != nil {
+		t.Errorf("unexpected reload error: %v", err)
+	}
+}
+
+func TestWatchFSNotify(t *testing.T) {
+	path := setupConfigDir(t)
+	writeFile(t, path, "name: first\n")
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.InitDefaults()
+	reloaded := make(chan struct{}, 1)
+	c.Subscribe("name", func([]string) {
+		select {
+		case reloaded <- struct{}{}:
+		default:
+		}
+	})
+	stop, err := c.Watch(koanfadapter.UseFSNotify())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	writeFile(t, path, "name: second one\n")
+	timeout := time.After(2 * time.Second)
+	for c.GetString("name") != "second one" { // writing may produce more than one event
+		select {
+		case <-reloaded:
+		case <-timeout:
+			t.Fatalf("expected configuration to be reloaded, name = %q", c.GetString("name"))
+		}
+	}
+	if _, err := koanfadapter.New(nil, "", nil).Watch(koanfadapter.UseFSNotify()); err == nil {
+		t.Errorf("expected watching without configuration files to fail")
+	}
+}
+
+func TestReloadKeepsValuesSetAndFilesLoaded(t *testing.T) {
+	const n = 20
+	path := setupConfigDir(t)
+	writeFile(t, path, "name: test\n")
+	dir := t.TempDir()
+	c := koanfadapter.New(nil, "watchtest", []string{"nt"})
+	c.InitDefaults()
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for range n {
+			if _, err := c.Reload(); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range n {
+			c.Set(fmt.Sprintf("set.k%02d", i), i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range n {
+			extra := filepath.Join(dir, fmt.Sprintf("extra%02d.nt", i))
+			writeFile(t, extra, fmt.Sprintf("loaded:\n    k%02d: %d\n", i, i))
+			if err := c.LoadFile(extra); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	wg.Wait()
+	if _, err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	for i := range n {
+		for _, key := range []string{fmt.Sprintf("set.k%02d", i), fmt.Sprintf("loaded.k%02d", i)} {
+			if v := c.GetInt(key); v != i {
+				t.Errorf("expected %s = %d after reloading, got %d", key, i, v)
+			}
+		}
+	}
+	if c.GetString("name") != "test" {
+		t.Errorf("expected located file to be loaded")
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/npillmayer/schuko"
 )
@@ -38,14 +39,18 @@ import (
 //
 // Deprecated: Use testconfig Conf instead.
 type Conf struct {
-	values map[string]string
+	values  map[string]string
+	origins map[string]schuko.Origin
 }
 
 // New creates a new configuration suited for testing.
 //
 // Deprecated: Use testconfig Conf instead.
 func New() *Conf {
-	return &Conf{values: make(map[string]string)}
+	return &Conf{
+		values:  make(map[string]string),
+		origins: make(map[string]schuko.Origin),
+	}
 }
 
 // Initialize initializes a configuration, populating it with defaullt values.
@@ -61,12 +66,15 @@ func (c *Conf) InitDefaults() {
 	m["tracing.adapter"] = "test"
 	etc := os.Getenv("GOPATH") + "/etc"
 	m["etc-dir"] = etc
+	c.origins["tracing.adapter"] = schuko.Origin{Kind: schuko.SourceDefault}
+	c.origins["etc-dir"] = schuko.Origin{Kind: schuko.SourceDefault}
 }
 
 // Set overrides the config value for key.
 func (c *Conf) Set(key string, value string) (oldval string) {
 	oldval = c.values[key]
 	c.values[key] = value
+	c.origins[key] = schuko.Origin{Kind: schuko.SourceSet}
 	return
 }
 
@@ -98,7 +106,113 @@ func (c *Conf) GetBool(key string) bool {
 	if !found {
 		return false
 	}
-	return strings.EqualFold(v, "true")
+	b, _ := schuko.ToBool(v)
+	return b
+}
+
+// GetFloat64 is part of the interface TypedConfiguration
+func (c *Conf) GetFloat64(key string) float64 {
+	f, _ := schuko.ToFloat64(c.values[key])
+	return f
+}
+
+// GetDuration is part of the interface TypedConfiguration
+func (c *Conf) GetDuration(key string) time.Duration {
+	d, _ := schuko.ToDuration(c.values[key])
+	return d
+}
+
+// GetTime is part of the interface TypedConfiguration
+func (c *Conf) GetTime(key string) time.Time {
+	t, _ := schuko.ToTime(c.values[key])
+	return t
+}
+
+// GetStringSlice is part of the interface TypedConfiguration
+func (c *Conf) GetStringSlice(key string) []string {
+	l, _ := schuko.ToStringSlice(c.values[key])
+	return l
+}
+
+// GetStringMap is part of the interface TypedConfiguration.
+// A key not present will be interpreted as a prefix, collecting all
+// entries "key.<subkey>" (or "key/<subkey>"), splitting sub-keys at dots into
+// nested maps.
+func (c *Conf) GetStringMap(key string) map[string]any {
+	if v, found := c.values[key]; found {
+		m, _ := schuko.ToStringMap(v)
+		return m
+	}
+	var flat map[string]any
+	for k, v := range c.values {
+		sub, ok := strings.CutPrefix(k, key+".")
+		if !ok {
+			sub, ok = strings.CutPrefix(k, key+"/")
+		}
+		if ok && sub != "" {
+			if flat == nil {
+				flat = make(map[string]any)
+			}
+			flat[sub] = v
+		}
+	}
+	if flat == nil {
+		return nil
+	}
+	return schuko.Unflatten(flat)
+}
+
+// LookupString is part of the interface StrictConfiguration
+func (c *Conf) LookupString(key string) (string, error) {
+	v, found := c.values[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToString)
+}
+
+// LookupInt is part of the interface StrictConfiguration
+func (c *Conf) LookupInt(key string) (int, error) {
+	v, found := c.values[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToInt)
+}
+
+// LookupBool is part of the interface StrictConfiguration
+func (c *Conf) LookupBool(key string) (bool, error) {
+	v, found := c.values[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToBool)
+}
+
+// LookupFloat64 is part of the interface StrictConfiguration
+func (c *Conf) LookupFloat64(key string) (float64, error) {
+	v, found := c.values[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToFloat64)
+}
+
+// LookupDuration is part of the interface StrictConfiguration
+func (c *Conf) LookupDuration(key string) (time.Duration, error) {
+	v, found := c.values[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToDuration)
+}
+
+// LookupTime is part of the interface StrictConfiguration
+func (c *Conf) LookupTime(key string) (time.Time, error) {
+	v, found := c.values[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToTime)
+}
+
+// LookupStringSlice is part of the interface StrictConfiguration
+func (c *Conf) LookupStringSlice(key string) ([]string, error) {
+	v, found := c.values[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToStringSlice)
+}
+
+// LookupStringMap is part of the interface StrictConfiguration
+func (c *Conf) LookupStringMap(key string) (map[string]any, error) {
+	if _, found := c.values[key]; !found {
+		if m := c.GetStringMap(key); m != nil {
+			return m, nil
+		}
+	}
+	v, found := c.values[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToStringMap)
 }
 
 // IsInteractive is a predicate: are we running in interactive mode?
@@ -106,4 +220,42 @@ func (c *Conf) GetBool(key string) bool {
 // Deprecated: A custom configuration key should be used instead.
 func (c *Conf) IsInteractive() bool { return false }
 
-var _ schuko.Configuration = &Conf{}
+// Origin is part of the interface ProvenanceConfiguration
+func (c *Conf) Origin(key string) (schuko.Origin, bool) {
+	if _, found := c.values[key]; !found {
+		return schuko.Origin{}, false
+	}
+	return c.origins[key], true
+}
+
+// Origins is part of the interface ProvenanceConfiguration
+func (c *Conf) Origins() map[string]schuko.Origin {
+	origins := make(map[string]schuko.Origin, len(c.values))
+	for key := range c.values {
+		origins[key] = c.origins[key]
+	}
+	return origins
+}
+
+// Keys is part of the interface EnumerableConfiguration
+func (c *Conf) Keys(prefix string) []string {
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	return schuko.FilterKeys(keys, prefix)
+}
+
+// AllSettings is part of the interface EnumerableConfiguration
+func (c *Conf) AllSettings() map[string]any {
+	flat := make(map[string]any, len(c.values))
+	for k, v := range c.values {
+		flat[k] = v
+	}
+	return schuko.Unflatten(flat)
+}
+
+var _ schuko.TypedConfiguration = &Conf{}
+var _ schuko.StrictConfiguration = &Conf{}
+var _ schuko.ProvenanceConfiguration = &Conf{}
+var _ schuko.EnumerableConfiguration = &Conf{}
@@ -20,8 +20,8 @@ package testconfig
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/npillmayer/schuko"
 )
@@ -66,15 +66,7 @@ func (c Conf) GetInt(key string) int {
 	if !found {
 		return 0
 	}
-	var n int
-	switch x := v.(type) {
-	case string:
-		n, _ = strconv.Atoi(x)
-	case int, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-		n, _ = x.(int)
-	default:
-		n = 0
-	}
+	n, _ := schuko.ToInt(v)
 	return n
 }
 
@@ -84,21 +76,165 @@ func (c Conf) GetBool(key string) bool {
 	if !found {
 		return false
 	}
-	var b bool
-	switch x := v.(type) {
-	case string:
-		b = strings.EqualFold(x, "true")
-	case bool:
-		b = x
-	default:
-		b = false
-	}
+	b, _ := schuko.ToBool(v)
 	return b
 }
 
+// GetFloat64 is part of the interface TypedConfiguration
+func (c Conf) GetFloat64(key string) float64 {
+	f, _ := schuko.ToFloat64(c[key])
+	return f
+}
+
+// GetDuration is part of the interface TypedConfiguration
+func (c Conf) GetDuration(key string) time.Duration {
+	d, _ := schuko.ToDuration(c[key])
+	return d
+}
+
+// GetTime is part of the interface TypedConfiguration
+func (c Conf) GetTime(key string) time.Time {
+	t, _ := schuko.ToTime(c[key])
+	return t
+}
+
+// GetStringSlice is part of the interface TypedConfiguration
+func (c Conf) GetStringSlice(key string) []string {
+	l, _ := schuko.ToStringSlice(c[key])
+	return l
+}
+
+// GetStringMap is part of the interface TypedConfiguration.
+//
+// As keys of a Conf are flat, a key not present will be interpreted as a
+// prefix: GetStringMap("db") will collect all entries "db.<subkey>" (or
+// "db/<subkey>"), splitting sub-keys at dots into nested maps.
+func (c Conf) GetStringMap(key string) map[string]any {
+	if v, found := c[key]; found {
+		m, _ := schuko.ToStringMap(v)
+		return m
+	}
+	return collectMap(c, key)
+}
+
+// LookupString is part of the interface StrictConfiguration
+func (c Conf) LookupString(key string) (string, error) {
+	v, found := c[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToString)
+}
+
+// LookupInt is part of the interface StrictConfiguration
+func (c Conf) LookupInt(key string) (int, error) {
+	v, found := c[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToInt)
+}
+
+// LookupBool is part of the interface StrictConfiguration
+func (c Conf) LookupBool(key string) (bool, error) {
+	v, found := c[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToBool)
+}
+
+// LookupFloat64 is part of the interface StrictConfiguration
+func (c Conf) LookupFloat64(key string) (float64, error) {
+	v, found := c[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToFloat64)
+}
+
+// LookupDuration is part of the interface StrictConfiguration
+func (c Conf) LookupDuration(key string) (time.Duration, error) {
+	v, found := c[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToDuration)
+}
+
+// LookupTime is part of the interface StrictConfiguration
+func (c Conf) LookupTime(key string) (time.Time, error) {
+	v, found := c[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToTime)
+}
+
+// LookupStringSlice is part of the interface StrictConfiguration
+func (c Conf) LookupStringSlice(key string) ([]string, error) {
+	v, found := c[key]
+	return schuko.ConvertValue(key, v, found, schuko.ToStringSlice)
+}
+
+// LookupStringMap is part of the interface StrictConfiguration.
+// Like GetStringMap, it interprets a key not present as a prefix.
+func (c Conf) LookupStringMap(key string) (map[string]any, error) {
+	v, found := c[key]
+	if !found {
+		if m := collectMap(c, key); m != nil {
+			return m, nil
+		}
+	}
+	return schuko.ConvertValue(key, v, found, schuko.ToStringMap)
+}
+
+// Origin is part of the interface ProvenanceConfiguration.
+// A Conf does not know where its values came from, therefore all keys present
+// are reported as set at runtime.
+func (c Conf) Origin(key string) (schuko.Origin, bool) {
+	if _, found := c[key]; !found {
+		return schuko.Origin{}, false
+	}
+	return schuko.Origin{Kind: schuko.SourceSet}, true
+}
+
+// Origins is part of the interface ProvenanceConfiguration.
+func (c Conf) Origins() map[string]schuko.Origin {
+	origins := make(map[string]schuko.Origin, len(c))
+	for key := range c {
+		origins[key] = schuko.Origin{Kind: schuko.SourceSet}
+	}
+	return origins
+}
+
 // IsInteractive is a predicate: are we running in interactive mode?
 //
 // Deprecated: A custom configuration key should be used instead.
 func (c Conf) IsInteractive() bool { return false }
 
-var _ schuko.Configuration = &Conf{}
+// Keys is part of the interface EnumerableConfiguration
+func (c Conf) Keys(prefix string) []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return schuko.FilterKeys(keys, prefix)
+}
+
+// AllSettings is part of the interface EnumerableConfiguration.
+// Keys are split at dots into nested maps.
+func (c Conf) AllSettings() map[string]any {
+	return schuko.Unflatten(c)
+}
+
+var _ schuko.TypedConfiguration = &Conf{}
+var _ schuko.StrictConfiguration = &Conf{}
+var _ schuko.EnumerableConfiguration = &Conf{}
+
+// collectMap collects all entries of flat map m with keys starting with
+// "prefix." or, as tolerated by trace2go and schuko.Sub, "prefix/". Key
+// segments are split at dots and result in nested maps (see schuko.Unflatten).
+// Returns nil if no entry matches.
+func collectMap(m map[string]any, prefix string) map[string]any {
+	var flat map[string]any
+	for k, v := range m {
+		sub, ok := strings.CutPrefix(k, prefix+".")
+		if !ok {
+			sub, ok = strings.CutPrefix(k, prefix+"/")
+		}
+		if !ok || sub == "" {
+			continue
+		}
+		if flat == nil {
+			flat = make(map[string]any)
+		}
+		flat[sub] = v
+	}
+	if flat == nil {
+		return nil
+	}
+	return schuko.Unflatten(flat)
+}
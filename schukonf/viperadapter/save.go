@@ -0,0 +1,67 @@
+package viperadapter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/npillmayer/schuko"
+	"github.com/spf13/afero"
+	"github.com/spf13/viper"
+)
+
+// Save writes the values set by calls to Set back to the configuration file
+// of the application (see schuko.SaveTarget), i.e. to the main file found by
+// schuko.LocateConfig, or to a new file in the application's directory below
+// os.UserConfigDir. It returns the path of the file written.
+//
+// Save is a shortcut for SaveTo(schuko.SaveTarget(…)); see SaveTo for details.
+func (c *VConf) Save() (string, error) {
+	path, err := schuko.SaveTarget(c.name, viper.SupportedExts)
+	if err != nil {
+		return "", err
+	}
+	return path, c.SaveTo(path)
+}
+
+// SaveTo writes the values set by calls to Set to a configuration file.
+// Values of an existing file are preserved, i.e. values set are merged into
+// them, and the file keeps its format. Values of other sources, e.g. other
+// files or defaults, are not written. The format of a new file is determined
+// by its extension, which has to be one of viper.SupportedExts. Directories
+// are created as needed, and the file is replaced atomically (see
+// schuko.WriteFileAtomic).
+func (c *VConf) SaveTo(path string) error {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	fv := viper.New()
+	fv.SetConfigType(ext)
+	if _, err := os.Stat(path); err == nil {
+		fv.SetConfigFile(path)
+		if err := fv.ReadInConfig(); err != nil {
+			return fmt.Errorf("config file %q: %w", path, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for key, o := range c.origins {
+		if o.Kind == schuko.SourceSet {
+			fv.Set(key, c.v.Get(key))
+		}
+	}
+	// Viper writes to files only, so we let it write to memory first
+	mem := afero.NewMemMapFs()
+	fv.SetFs(mem)
+	out := "/config." + ext
+	if err := fv.WriteConfigAs(out); err != nil {
+		return fmt.Errorf("config file %q: %w", path, err)
+	}
+	b, err := afero.ReadFile(mem, out)
+	if err != nil {
+		return err
+	}
+	return schuko.WriteFileAtomic(path, b, 0o644)
+}
+
+var _ schuko.PersistentConfiguration = &VConf{}
@@ -6,6 +6,12 @@ All configuration is started explicitely with a call to
 	conf := viperadapter.New("myapp")
 	conf.InitDefaults()
 
+Every VConf wraps its own viper.Viper instance, i.e. the global viper
+configuration is not touched. Clients who want to use a pre-configured
+Viper may wrap it with
+
+	conf := viperadapter.NewWithViper(v, "myapp")
+
 There is no init-call to set up configuration a priori. The reason
 is to avoid coupling to a specific configuration framework, but rather
 relay this decision to the client.
@@ -22,6 +28,8 @@ package viperadapter
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/npillmayer/schuko"
 	"github.com/spf13/viper"
@@ -29,13 +37,30 @@ import (
 
 // VConf respresents a Viper configuration.
 type VConf struct {
-	name string
+	name    string
+	v       *viper.Viper
+	origins map[string]schuko.Origin // provenance of values, by lower-case key
 }
 
-// New creates a new Viper configuration adapter.
+// New creates a new Viper configuration adapter with a fresh Viper instance.
 // `name` is used as a tag to locate application-configuration.
 func New(name string) *VConf {
-	return &VConf{name: name}
+	return NewWithViper(viper.New(), name)
+}
+
+// NewWithViper creates a new configuration adapter for an existing Viper
+// instance. If v is nil, a new Viper will be created.
+// `name` is used as a tag to locate application-configuration.
+func NewWithViper(v *viper.Viper, name string) *VConf {
+	if v == nil {
+		v = viper.New()
+	}
+	return &VConf{name: name, v: v, origins: make(map[string]schuko.Origin)}
+}
+
+// Viper returns the embedded Viper configuration-object.
+func (c *VConf) Viper() *viper.Viper {
+	return c.v
 }
 
 // Init is usually called by schuko.Initialize()
@@ -46,54 +71,207 @@ func (c *VConf) Init() {
 
 // InitDefaults sets up
 func (c *VConf) InitDefaults() {
-	viper.SetDefault("tracing", "go")
-	viper.SetDefault("tracingonline", true)
+	c.v.SetDefault("tracing", "go")
+	c.v.SetDefault("tracingonline", true)
+	c.origins["tracing"] = schuko.Origin{Kind: schuko.SourceDefault}
+	c.origins["tracingonline"] = schuko.Origin{Kind: schuko.SourceDefault}
 }
 
 // InitConfigPath is usually called by Init().
-// It sets up a standard config path and searches for application configuration
-// files.
+// It searches for application configuration files with schuko.LocateConfig,
+// thus using the same locations as the other configuration adapters.
+// Files of all formats supported by Viper are considered; if more than one
+// file is found, they are merged in the order located.
+//
+// Unlike package koanfadapter, VConf does not support include directives: a
+// key "include" is an ordinary configuration value.
 func (c *VConf) InitConfigPath() {
-	viper.SetConfigName(c.name)                    // name of config file (without extension)
-	viper.AddConfigPath(".")                       // optionally look for config in the working directory
-	viper.AddConfigPath("$HOME/." + c.name)        // call multiple times to add many search paths
-	viper.AddConfigPath("$HOME/.config/" + c.name) // call multiple times to add many search paths
-	err := viper.ReadInConfig()                    // Find and read the config file
-	if err != nil {                                // Handle errors reading the config file
-		fmt.Fprintf(os.Stderr, "error reading config file: %s", err.Error())
+	files := schuko.LocateConfig(c.name, "", viper.SupportedExts)
+	for _, path := range files {
+		// read each file into a Viper of its own, to know which keys it sets
+		fv := viper.New()
+		fv.SetConfigFile(path)
+		err := fv.ReadInConfig()
+		if err == nil {
+			err = c.v.MergeConfigMap(fv.AllSettings())
+		}
+		if err != nil { // Handle errors reading the config file
+			fmt.Fprintf(os.Stderr, "error reading config file: %s\n", err.Error())
+			continue
+		}
+		c.v.SetConfigFile(path)
+		c.recordFile(path, fv.AllKeys())
+	}
+}
+
+// recordFile records keys as originating from configuration file path.
+func (c *VConf) recordFile(path string, keys []string) {
+	for _, key := range keys {
+		if c.origins[key].Kind == schuko.SourceSet {
+			continue // values set by Set(…) take precedence over files
+		}
+		c.origins[key] = schuko.Origin{Kind: schuko.SourceFile, Name: path}
 	}
 }
 
 // Set overrides any configuration values.
 func (c *VConf) Set(key string, value any) {
-	viper.Set(key, value)
+	c.v.Set(key, value)
+	c.origins[strings.ToLower(key)] = schuko.Origin{Kind: schuko.SourceSet}
 }
 
 // IsSet is a predicate wether a configuration flag is set to true.
 func (c *VConf) IsSet(key string) bool {
-	return viper.IsSet(key)
+	return c.v.IsSet(key)
 }
 
 // GetString returns a configuration property as a string.
 func (c *VConf) GetString(key string) string {
-	return viper.GetString(key)
+	return c.v.GetString(key)
 }
 
 // GetInt returns a configuration property as an integer.
 func (c *VConf) GetInt(key string) int {
-	return viper.GetInt(key)
+	return c.v.GetInt(key)
 }
 
 // GetBool returns a configuration property as a boolean value.
 func (c *VConf) GetBool(key string) bool {
-	return viper.GetBool(key)
+	return c.v.GetBool(key)
+}
+
+// GetFloat64 returns a configuration property as a float.
+func (c *VConf) GetFloat64(key string) float64 {
+	f, _ := schuko.ToFloat64(c.v.Get(key))
+	return f
+}
+
+// GetDuration returns a configuration property as a time.Duration.
+func (c *VConf) GetDuration(key string) time.Duration {
+	d, _ := schuko.ToDuration(c.v.Get(key))
+	return d
+}
+
+// GetTime returns a configuration property as a time.Time.
+func (c *VConf) GetTime(key string) time.Time {
+	t, _ := schuko.ToTime(c.v.Get(key))
+	return t
+}
+
+// GetStringSlice returns a configuration property as a list of strings.
+func (c *VConf) GetStringSlice(key string) []string {
+	l, _ := schuko.ToStringSlice(c.v.Get(key))
+	return l
+}
+
+// GetStringMap returns a configuration property as a dictionary.
+func (c *VConf) GetStringMap(key string) map[string]any {
+	m, _ := schuko.ToStringMap(c.v.Get(key))
+	return m
+}
+
+// LookupString returns a configuration property as a string, or an error
+// if the key is not set or the value cannot be converted.
+func (c *VConf) LookupString(key string) (string, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToString)
+}
+
+// LookupInt returns a configuration property as an integer, or an error
+// if the key is not set or the value cannot be converted.
+func (c *VConf) LookupInt(key string) (int, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToInt)
+}
+
+// LookupBool returns a configuration property as a boolean value, or an error
+// if the key is not set or the value cannot be converted.
+func (c *VConf) LookupBool(key string) (bool, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToBool)
+}
+
+// LookupFloat64 returns a configuration property as a float, or an error
+// if the key is not set or the value cannot be converted.
+func (c *VConf) LookupFloat64(key string) (float64, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToFloat64)
+}
+
+// LookupDuration returns a configuration property as a time.Duration, or an error
+// if the key is not set or the value cannot be converted.
+func (c *VConf) LookupDuration(key string) (time.Duration, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToDuration)
+}
+
+// LookupTime returns a configuration property as a time.Time, or an error
+// if the key is not set or the value cannot be converted.
+func (c *VConf) LookupTime(key string) (time.Time, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToTime)
+}
+
+// LookupStringSlice returns a configuration property as a list of strings, or an error
+// if the key is not set or the value cannot be converted.
+func (c *VConf) LookupStringSlice(key string) ([]string, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToStringSlice)
+}
+
+// LookupStringMap returns a configuration property as a dictionary, or an error
+// if the key is not set or the value cannot be converted.
+func (c *VConf) LookupStringMap(key string) (map[string]any, error) {
+	v, found := c.raw(key)
+	return schuko.ConvertValue(key, v, found, schuko.ToStringMap)
 }
 
 // IsInteractive is a predicate: are we running in interactive mode?
 //
 // Deprecated: A custom configuration key should be used instead.
 func (c *VConf) IsInteractive() bool {
-	return viper.GetBool("tracingonline")
+	return c.v.GetBool("tracingonline")
 }
 
-var _ schuko.Configuration = &VConf{}
+// Origin returns the origin of the value for key: a default, a configuration
+// file or a call to Set. Values which Viper got by other means, e.g. from
+// environment variables bound by the client, are of origin kind SourceUnknown.
+func (c *VConf) Origin(key string) (schuko.Origin, bool) {
+	if !c.v.IsSet(key) {
+		return schuko.Origin{}, false
+	}
+	return c.origins[strings.ToLower(key)], true
+}
+
+// Origins returns the origins of all keys known to Viper.
+func (c *VConf) Origins() map[string]schuko.Origin {
+	keys := c.v.AllKeys()
+	origins := make(map[string]schuko.Origin, len(keys))
+	for _, key := range keys {
+		origins[key] = c.origins[key]
+	}
+	return origins
+}
+
+// Keys returns all keys known to Viper, restricted to prefix. Viper keys
+// are lower-case.
+func (c *VConf) Keys(prefix string) []string {
+	return schuko.FilterKeys(c.v.AllKeys(), strings.ToLower(prefix))
+}
+
+// AllSettings returns all settings known to Viper as a nested dictionary.
+func (c *VConf) AllSettings() map[string]any {
+	return c.v.AllSettings()
+}
+
+var _ schuko.TypedConfiguration = &VConf{}
+var _ schuko.StrictConfiguration = &VConf{}
+var _ schuko.ProvenanceConfiguration = &VConf{}
+var _ schuko.EnumerableConfiguration = &VConf{}
+
+func (c *VConf) raw(key string) (any, bool) {
+	if !c.v.IsSet(key) {
+		return nil, false
+	}
+	return c.v.Get(key), true
+}
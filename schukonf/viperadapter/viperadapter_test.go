@@ -0,0 +1,111 @@
+package viperadapter_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/viperadapter"
+	"github.com/spf13/viper"
+)
+
+func TestInstancesAreIndependent(t *testing.T) {
+	c1 := viperadapter.New("app1")
+	c2 := viperadapter.New("app2")
+	c1.Set("name", "one")
+	c2.Set("name", "two")
+	if n := c1.GetString("name"); n != "one" {
+		t.Errorf("expected name = one, got %q", n)
+	}
+	if n := c2.GetString("name"); n != "two" {
+		t.Errorf("expected name = two, got %q", n)
+	}
+	if viper.IsSet("name") {
+		t.Errorf("expected global viper to be untouched")
+	}
+}
+
+func TestNewWithViper(t *testing.T) {
+	v := viper.New()
+	v.Set("tracing.adapter", "logrus")
+	c := viperadapter.NewWithViper(v, "myapp")
+	if c.Viper() != v {
+		t.Errorf("expected adapter to wrap the Viper instance passed in")
+	}
+	if a := c.GetString("tracing.adapter"); a != "logrus" {
+		t.Errorf("expected tracing.adapter = logrus, got %q", a)
+	}
+}
+
+func TestInitConfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	dir := filepath.Join(home, ".config", "vipertest")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	yaml := "tracing:\n  adapter: logrus\nname: yaml\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	json := `{"port": 8080}`
+	if err := os.WriteFile(filepath.Join(dir, "vipertest.json"), []byte(json), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := viperadapter.New("vipertest")
+	c.Init()
+	if a := c.GetString("tracing.adapter"); a != "logrus" {
+		t.Errorf("expected tracing.adapter = logrus, got %q", a)
+	}
+	if p := c.GetInt("port"); p != 8080 {
+		t.Errorf("expected port = 8080, got %d", p)
+	}
+	if o, _ := c.Origin("port"); o.Kind != schuko.SourceFile || o.Name != filepath.Join(dir, "vipertest.json") {
+		t.Errorf("expected port to originate from vipertest.json, got %v", o)
+	}
+	if o, _ := c.Origin("tracing.adapter"); o.Name != filepath.Join(dir, "config.yaml") {
+		t.Errorf("expected tracing.adapter to originate from config.yaml, got %v", o)
+	}
+}
+
+func TestSave(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	c := viperadapter.New("savetest")
+	c.Init()
+	c.Set("ui.theme", "dark")
+	path, err := c.Save()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := filepath.Join(home, ".config", "savetest", "config.json"); path != expected {
+		t.Errorf("expected new file %q, got %q", expected, path)
+	}
+	c = viperadapter.New("savetest")
+	c.Init()
+	if th := c.GetString("ui.theme"); th != "dark" {
+		t.Errorf("expected saved value to be loaded, got %q", th)
+	}
+	if c.IsSet("tracingonline") && c.GetString("tracingonline") != "true" {
+		t.Errorf("expected defaults not to be saved")
+	}
+	c.Set("ui.font", "mono")
+	if _, err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("saved:\n%s", b)
+	if !strings.Contains(string(b), `"theme": "dark"`) || !strings.Contains(string(b), `"font": "mono"`) {
+		t.Errorf("expected existing and new values to be saved, got %s", b)
+	}
+	if strings.Contains(string(b), "tracingonline") {
+		t.Errorf("expected defaults not to be saved, got %s", b)
+	}
+}
@@ -0,0 +1,279 @@
+package schuko
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SearchRule describes a set of locations to search for configuration files.
+// Rules are combined into a SearchPath.
+type SearchRule struct {
+	Name     string                       // name of the rule, reported for files found
+	Dirs     func(appTag string) []string // candidate directories, highest precedence first
+	DropIns  bool                         // search drop-in directories (see DropInDir)
+	Required bool                         // locations have to exist (see ConfigFile)
+}
+
+// UserConfigRule searches the application's directory below os.UserConfigDir,
+// with the directory named either as given by the tag or in lower case.
+// On *nix-systems this is usually covered by XDGConfigHomeRule as well.
+func UserConfigRule() SearchRule {
+	return SearchRule{Name: "user", DropIns: true, Dirs: func(appTag string) []string {
+		confdir, err := os.UserConfigDir()
+		if err != nil {
+			return nil
+		}
+		if home, err := os.UserHomeDir(); err != nil || home == confdir {
+			return nil
+		}
+		return []string{filepath.Join(confdir, appTag), filepath.Join(confdir, strings.ToLower(appTag))}
+	}}
+}
+
+// XDGConfigHomeRule searches $XDG_CONFIG_HOME/<tag>. If $XDG_CONFIG_HOME is
+// unset or not an absolute path, $HOME/.config/<tag> is searched.
+func XDGConfigHomeRule() SearchRule {
+	return SearchRule{Name: "xdg-home", DropIns: true, Dirs: func(appTag string) []string {
+		confdir := os.Getenv("XDG_CONFIG_HOME")
+		if !filepath.IsAbs(confdir) {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil
+			}
+			confdir = filepath.Join(home, ".config")
+		}
+		return []string{filepath.Join(confdir, strings.ToLower(appTag))}
+	}}
+}
+
+// XDGConfigDirsRule searches the system-wide directories given by
+// $XDG_CONFIG_DIRS, in order of their precedence, i.e. <dir>/<tag> for every
+// directory listed. If $XDG_CONFIG_DIRS is unset, /etc/xdg is used.
+func XDGConfigDirsRule() SearchRule {
+	return SearchRule{Name: "xdg-dirs", DropIns: true, Dirs: func(appTag string) []string {
+		list := os.Getenv("XDG_CONFIG_DIRS")
+		if list == "" {
+			list = "/etc/xdg"
+		}
+		var dirs []string
+		for _, dir := range filepath.SplitList(list) {
+			if dir != "" {
+				dirs = append(dirs, filepath.Join(dir, strings.ToLower(appTag)))
+			}
+		}
+		return dirs
+	}}
+}
+
+// SystemRule searches /etc/<tag>, as is common for daemons.
+func SystemRule() SearchRule {
+	return SearchRule{Name: "system", DropIns: true, Dirs: func(appTag string) []string {
+		return []string{filepath.Join("/etc", strings.ToLower(appTag))}
+	}}
+}
+
+// WorkingDirRule searches the current working directory.
+func WorkingDirRule() SearchRule {
+	return SearchRule{Name: "cwd", Dirs: func(string) []string {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil
+		}
+		return []string{wd}
+	}}
+}
+
+// HomeRule searches $HOME, usually for files named .<tag>.<suffix>.
+func HomeRule() SearchRule {
+	return SearchRule{Name: "home", Dirs: func(string) []string {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		return []string{home}
+	}}
+}
+
+// ConfigEnvName returns the name of the environment variable overriding the
+// location of the configuration of an application, e.g. MYAPP_CONFIG for tag
+// "myapp" (see EnvOverrideRule).
+func ConfigEnvName(appTag string) string {
+	return strings.ToUpper(appTag) + "_CONFIG"
+}
+
+// EnvOverrideRule uses the location given by environment variable <TAG>_CONFIG
+// (see ConfigEnvName). The location may either be a directory, which is
+// searched as usual, or a configuration file, which is taken regardless of
+// its name. The location is required to exist: if it does not, Locate reports
+// it as a missing file (see ConfigFile), which fails to load.
+func EnvOverrideRule() SearchRule {
+	return SearchRule{Name: "env", DropIns: true, Required: true, Dirs: func(appTag string) []string {
+		if path := os.Getenv(ConfigEnvName(appTag)); path != "" {
+			return []string{path}
+		}
+		return nil
+	}}
+}
+
+// ConfigFile is a configuration file found by SearchPath.Locate.
+// For locations of required rules which do not exist, Locate reports a
+// ConfigFile with Missing set, and Path and Dir being the location.
+type ConfigFile struct {
+	Path    string // path of the file
+	Dir     string // directory searched, or the file itself for explicitly given files
+	Rule    string // name of the rule which found the file
+	Missing bool   // location of a required rule does not exist
+}
+
+// SearchPath is a configurable list of rules for locating configuration
+// files. Rules are given in order of precedence, highest first.
+type SearchPath struct {
+	rules      []SearchRule
+	collectAll bool
+}
+
+// SearchOption is a type to configure search paths.
+// Multiple options may be passed to `NewSearchPath(…)`.
+type SearchOption func(*SearchPath)
+
+// WithRules sets the rules of a search path, highest precedence first.
+func WithRules(rules ...SearchRule) SearchOption {
+	return func(sp *SearchPath) {
+		sp.rules = rules
+	}
+}
+
+// CollectAll makes a search path collect the files of all directories
+// searched, instead of stopping at the first directory containing any.
+func CollectAll() SearchOption {
+	return func(sp *SearchPath) {
+		sp.collectAll = true
+	}
+}
+
+// StopAtFirst makes a search path stop at the first directory containing
+// any configuration file. This is the default.
+func StopAtFirst() SearchOption {
+	return func(sp *SearchPath) {
+		sp.collectAll = false
+	}
+}
+
+// NewSearchPath creates a search path. Without options, the rules of
+// DefaultSearchPath are used.
+func NewSearchPath(opts ...SearchOption) *SearchPath {
+	sp := &SearchPath{
+		rules: []SearchRule{UserConfigRule(), XDGConfigHomeRule(), HomeRule()},
+	}
+	for _, opt := range opts {
+		opt(sp)
+	}
+	return sp
+}
+
+// DefaultSearchPath returns the search path used by LocateConfig, i.e. rules
+// UserConfigRule, XDGConfigHomeRule and HomeRule, stopping at the first hit.
+func DefaultSearchPath() *SearchPath {
+	return NewSearchPath()
+}
+
+// SystemSearchPath returns a search path suited for daemons and system-wide
+// installations. It collects files of all of these rules, highest precedence
+// first: EnvOverrideRule, WorkingDirRule, UserConfigRule, XDGConfigHomeRule,
+// XDGConfigDirsRule and SystemRule. Options may modify the search path.
+func SystemSearchPath(opts ...SearchOption) *SearchPath {
+	opts = append([]SearchOption{
+		WithRules(EnvOverrideRule(), WorkingDirRule(), UserConfigRule(), XDGConfigHomeRule(),
+			XDGConfigDirsRule(), SystemRule()),
+		CollectAll(),
+	}, opts...)
+	return NewSearchPath(opts...)
+}
+
+// Rules returns the rules of the search path, highest precedence first.
+func (sp *SearchPath) Rules() []SearchRule {
+	return sp.rules
+}
+
+// Locate searches for configuration files of an application. Files have to
+// be named as described for LocateConfig; pattern and suffixes are used as
+// with LocateConfig. Directories are searched in the order of the rules,
+// stopping at the first directory containing any configuration file, unless
+// option CollectAll is set. Directories found by more than one rule are
+// searched once only. Locations of required rules which do not exist are
+// reported as missing files (see ConfigFile).
+//
+// Files are returned in merge order, i.e. files later in the list are meant
+// to override values of files earlier in the list. With CollectAll, the files
+// of the rule with the lowest precedence therefore come first.
+func (sp *SearchPath) Locate(appTag, pattern string, suffixes []string) []ConfigFile {
+	if appTag == "" || len(suffixes) == 0 {
+		return nil
+	}
+	tag := strings.ToLower(appTag)
+	seen := make(map[string]bool)
+	var groups [][]ConfigFile
+	for _, rule := range sp.rules {
+		if rule.Dirs == nil {
+			continue
+		}
+		for _, dir := range rule.Dirs(appTag) {
+			dir = filepath.Clean(dir)
+			if seen[dir] {
+				continue
+			}
+			seen[dir] = true
+			var group []ConfigFile
+			if _, err := os.Stat(dir); rule.Required && errors.Is(err, os.ErrNotExist) {
+				group = []ConfigFile{{Path: dir, Dir: dir, Rule: rule.Name, Missing: true}}
+			}
+			for _, path := range locateIn(dir, tag, pattern, suffixes, rule.DropIns) {
+				group = append(group, ConfigFile{Path: path, Dir: dir, Rule: rule.Name})
+			}
+			if len(group) == 0 {
+				continue
+			}
+			if !sp.collectAll {
+				return group
+			}
+			groups = append(groups, group)
+		}
+	}
+	var result []ConfigFile
+	for i := len(groups) - 1; i >= 0; i-- {
+		result = append(result, groups[i]...)
+	}
+	return result
+}
+
+// locateIn finds the configuration files within dir. If dir is a regular
+// file, it is returned as is.
+func locateIn(dir, tag, pattern string, suffixes []string, dropins bool) []string {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil
+	}
+	if fi.Mode().IsRegular() {
+		return []string{dir}
+	}
+	d, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	_, files := dirMatch(dir, d, tag, pattern, suffixes, dropins)
+	return files
+}
+
+// Paths returns the paths of configuration files.
+func Paths(files []ConfigFile) []string {
+	if len(files) == 0 {
+		return nil
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	return paths
+}
@@ -0,0 +1,135 @@
+package schuko_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+)
+
+// setupSearchDirs creates configuration files for tag "searchtest" in the XDG
+// home directory and in two system-wide XDG directories.
+func setupSearchDirs(t *testing.T) (home, sys1, sys2 string) {
+	root := t.TempDir()
+	home = filepath.Join(root, "home")
+	sys1, sys2 = filepath.Join(root, "sys1"), filepath.Join(root, "sys2")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_CONFIG_DIRS", sys1+string(os.PathListSeparator)+sys2)
+	t.Setenv("SEARCHTEST_CONFIG", "")
+	for _, dir := range []string{filepath.Join(home, ".config", "searchtest"),
+		filepath.Join(sys1, "searchtest"), filepath.Join(sys2, "searchtest")} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "config.nt"), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return
+}
+
+func TestSearchPathStopAtFirst(t *testing.T) {
+	home, _, _ := setupSearchDirs(t)
+	sp := schuko.NewSearchPath(schuko.WithRules(schuko.XDGConfigHomeRule(), schuko.XDGConfigDirsRule()))
+	files := sp.Locate("searchtest", "", []string{"nt"})
+	dir := filepath.Join(home, ".config", "searchtest")
+	expected := []schuko.ConfigFile{{Path: filepath.Join(dir, "config.nt"), Dir: dir, Rule: "xdg-home"}}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected %v, got %v", expected, files)
+	}
+}
+
+func TestSearchPathCollectAll(t *testing.T) {
+	home, sys1, sys2 := setupSearchDirs(t)
+	sp := schuko.SystemSearchPath()
+	files := sp.Locate("searchtest", "", []string{"nt"})
+	var rules []string
+	for _, f := range files {
+		rules = append(rules, f.Rule)
+	}
+	paths := schuko.Paths(files)
+	expected := []string{
+		filepath.Join(sys2, "searchtest", "config.nt"), // lowest precedence first
+		filepath.Join(sys1, "searchtest", "config.nt"),
+		filepath.Join(home, ".config", "searchtest", "config.nt"),
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected files in merge order %v, got %v", expected, paths)
+	}
+	if !reflect.DeepEqual(rules, []string{"xdg-dirs", "xdg-dirs", "user"}) {
+		t.Errorf("unexpected rules %v", rules)
+	}
+}
+
+func TestSearchPathEnvOverride(t *testing.T) {
+	setupSearchDirs(t)
+	override := filepath.Join(t.TempDir(), "special.yaml")
+	if err := os.WriteFile(override, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(schuko.ConfigEnvName("searchtest"), override)
+	sp := schuko.SystemSearchPath(schuko.StopAtFirst())
+	files := sp.Locate("searchtest", "", []string{"nt"})
+	if len(files) != 1 || files[0].Path != override || files[0].Rule != "env" {
+		t.Errorf("expected only the file given by environment, got %v", files)
+	}
+}
+
+func TestSearchPathWorkingDir(t *testing.T) {
+	setupSearchDirs(t)
+	wd, _ := os.Getwd()
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.WriteFile(filepath.Join(dir, "searchtest.nt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sp := schuko.NewSearchPath(schuko.WithRules(schuko.WorkingDirRule(), schuko.XDGConfigHomeRule()))
+	files := sp.Locate("searchtest", "", []string{"nt"})
+	if len(files) != 1 || filepath.Base(files[0].Path) != "searchtest.nt" || files[0].Rule != "cwd" {
+		t.Errorf("expected file in working directory, got %v", files)
+	}
+}
+
+func TestSystemRule(t *testing.T) {
+	dirs := schuko.SystemRule().Dirs("MyDaemon")
+	if !reflect.DeepEqual(dirs, []string{filepath.Join("/etc", "mydaemon")}) {
+		t.Errorf("unexpected system directories %v", dirs)
+	}
+}
+
+func TestXDGConfigHomeRule(t *testing.T) {
+	home := t.TempDir()
+	confdir := filepath.Join(t.TempDir(), "xdg")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", confdir)
+	dirs := schuko.XDGConfigHomeRule().Dirs("MyApp")
+	if expected := []string{filepath.Join(confdir, "myapp")}; !reflect.DeepEqual(dirs, expected) {
+		t.Errorf("expected %v, got %v", expected, dirs)
+	}
+	t.Setenv("XDG_CONFIG_HOME", "relative/xdg") // not absolute, to be ignored
+	dirs = schuko.XDGConfigHomeRule().Dirs("MyApp")
+	if expected := []string{filepath.Join(home, ".config", "myapp")}; !reflect.DeepEqual(dirs, expected) {
+		t.Errorf("expected %v, got %v", expected, dirs)
+	}
+}
+
+func TestSearchPathEnvOverrideMissing(t *testing.T) {
+	setupSearchDirs(t)
+	missing := filepath.Join(t.TempDir(), "missing.nt")
+	t.Setenv(schuko.ConfigEnvName("searchtest"), missing)
+	files := schuko.SystemSearchPath(schuko.StopAtFirst()).Locate("searchtest", "", []string{"nt"})
+	expected := []schuko.ConfigFile{{Path: missing, Dir: missing, Rule: "env", Missing: true}}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected missing file given by environment to be reported, got %v", files)
+	}
+	files = schuko.SystemSearchPath().Locate("searchtest", "", []string{"nt"})
+	if len(files) != 4 || !files[3].Missing {
+		t.Errorf("expected missing file to have highest precedence, got %v", files)
+	}
+}
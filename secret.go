@@ -0,0 +1,403 @@
+package schuko
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// Redacted is the text shown in place of secret values.
+const Redacted = "******"
+
+// Secret is a string holding a secret value, e.g. a password. Its formatting
+// methods never reveal the value, i.e. a Secret may safely be passed to
+// fmt-style functions, to slog and to tracers (tracing.Trace.P(…)).
+// Use Reveal to get at the value.
+type Secret string
+
+// Reveal returns the secret value in plain text.
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// String returns a redacted text.
+func (s Secret) String() string {
+	return Redacted
+}
+
+// GoString returns a redacted text, for format verb %#v.
+func (s Secret) GoString() string {
+	return Redacted
+}
+
+// LogValue makes Secret a slog.LogValuer, returning a redacted text.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// MarshalText returns a redacted text. It is used by encoders, e.g. for JSON.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// SecretConfiguration is an optional extension of Configuration for
+// configurations which know which of their keys hold secret values.
+// Use IsSecret to check a key of any configuration.
+type SecretConfiguration interface {
+	Configuration
+	IsSecret(key string) bool
+}
+
+// secretKeywords are endings of key segments marking keys as secret by convention.
+var secretKeywords = []string{"password", "passwd", "secret", "token", "apikey", "api_key",
+	"api-key", "credentials", "privatekey", "private_key", "private-key"}
+
+// IsSecretKey is a predicate wether a key is secret by naming convention,
+// i.e., its last segment ends with "password", "secret", "token", "apikey",
+// "credentials" or "privatekey" (ignoring case), as in "db.password" or
+// "github.api_token".
+func IsSecretKey(key string) bool {
+	seg := strings.ToLower(key[strings.LastIndexAny(key, "./")+1:])
+	for _, kw := range secretKeywords {
+		if strings.HasSuffix(seg, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSecret is a predicate wether the value of key in conf is secret. If conf
+// implements SecretConfiguration, it decides, otherwise the naming convention
+// of IsSecretKey applies.
+func IsSecret(conf Configuration, key string) bool {
+	if sc, ok := conf.(SecretConfiguration); ok {
+		return sc.IsSecret(key)
+	}
+	return IsSecretKey(key)
+}
+
+// Redact returns a copy of a nested dictionary of settings, as returned by
+// AllSettings, with values of secret keys replaced by Redacted. Keys are
+// checked with IsSecret(conf, …); conf may be nil, in which case the naming
+// convention of IsSecretKey applies.
+func Redact(conf Configuration, settings map[string]any) map[string]any {
+	isSecret := IsSecretKey
+	if conf != nil {
+		isSecret = func(key string) bool { return IsSecret(conf, key) }
+	}
+	return redactMap(settings, "", isSecret)
+}
+
+func redactMap(m map[string]any, prefix string, isSecret func(string) bool) map[string]any {
+	r := make(map[string]any, len(m))
+	for k, v := range m {
+		key := joinKey(prefix, k)
+		if isSecret(key) {
+			r[k] = Redacted
+		} else if sub, ok := v.(map[string]any); ok {
+			r[k] = redactMap(sub, key, isSecret)
+		} else {
+			r[k] = v
+		}
+	}
+	return r
+}
+
+// ErrSecretUnresolved is the cause of lookup errors for secret references
+// which cannot be resolved.
+var ErrSecretUnresolved = errors.New("cannot resolve secret")
+
+// SecretError describes a reference to a secret which failed to resolve.
+type SecretError struct {
+	Key string // configuration key holding the reference
+	Ref string // the reference, e.g. "file:///run/secrets/db"
+	Err error  // underlying error
+}
+
+func (e *SecretError) Error() string {
+	return fmt.Sprintf("%s for %q from %q: %s", ErrSecretUnresolved, e.Key, e.Ref, e.Err)
+}
+
+// Unwrap makes SecretError usable with errors.Is and errors.As.
+func (e *SecretError) Unwrap() []error {
+	return []error{ErrSecretUnresolved, e.Err}
+}
+
+// Secrets is a configuration which resolves the values of secret keys of
+// another configuration. Keys are secret if declared so by a schema (see option
+// Sensitive) or by naming convention (see IsSecretKey). Values of secret keys may
+// be references to the actual secret:
+//
+//	file:///run/secrets/db_password    // contents of a file, e.g. a Docker or Kubernetes secret
+//	env:DB_PASSWORD                    // value of an environment variable
+//
+// Other values are taken literally. Trailing newlines of secret files are
+// removed. References are resolved on every access, i.e. rotated secrets are
+// picked up. If a reference fails to resolve, the getters return an empty
+// string; LookupSecret and LookupString report an error wrapping a *SecretError.
+//
+// Explanations (see Explain) and exports of Secrets never show secret values.
+type Secrets struct {
+	conf   Configuration
+	schema *Schema
+}
+
+// WithSecrets creates a configuration on top of conf, resolving secret values.
+// schema may be nil, in which case keys are secret by naming convention only.
+func WithSecrets(conf Configuration, schema *Schema) *Secrets {
+	return &Secrets{conf: conf, schema: schema}
+}
+
+// IsSecret is a predicate wether the value of key is secret.
+func (c *Secrets) IsSecret(key string) bool {
+	if c.schema != nil && c.schema.IsSecret(key) {
+		return true
+	}
+	return IsSecret(c.conf, key)
+}
+
+// LookupSecret returns the resolved value of a secret key, or an error if
+// the key is not set or its reference cannot be resolved.
+func (c *Secrets) LookupSecret(key string) (Secret, error) {
+	if !c.conf.IsSet(key) {
+		_, err := ConvertValue(key, nil, false, ToString)
+		return "", err
+	}
+	ref := c.conf.GetString(key)
+	s, err := resolveSecret(key, ref)
+	if err != nil {
+		return "", &LookupError{Key: key, Type: "string", Value: Redacted, Err: ErrInvalidValue,
+			Cause: err}
+	}
+	return Secret(s), nil
+}
+
+// resolveSecret resolves a reference to a secret. Values which are not
+// references are returned unchanged.
+func resolveSecret(key, ref string) (string, error) {
+	if path, ok := strings.CutPrefix(ref, "file://"); ok {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", &SecretError{Key: key, Ref: ref, Err: err}
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	}
+	if name, ok := strings.CutPrefix(ref, "env:"); ok {
+		v, found := os.LookupEnv(name)
+		if !found {
+			return "", &SecretError{Key: key, Ref: ref, Err: errors.New("environment variable not set")}
+		}
+		return v, nil
+	}
+	return ref, nil
+}
+
+// secret returns the resolved value for a secret key, or "" if it fails to
+// resolve. For keys which are not secret, ok is false.
+func (c *Secrets) secret(key string) (s string, ok bool) {
+	if !c.IsSecret(key) || !c.conf.IsSet(key) {
+		return "", false
+	}
+	s, err := resolveSecret(key, c.conf.GetString(key))
+	if err != nil {
+		return "", true
+	}
+	return s, true
+}
+
+// InitDefaults calls InitDefaults of the underlying configuration.
+func (c *Secrets) InitDefaults() {
+	c.conf.InitDefaults()
+}
+
+// IsSet is a predicate wether the underlying configuration has key set.
+func (c *Secrets) IsSet(key string) bool {
+	return c.conf.IsSet(key)
+}
+
+// GetString returns a configuration property as a string. Values of secret
+// keys are resolved.
+func (c *Secrets) GetString(key string) string {
+	if s, ok := c.secret(key); ok {
+		return s
+	}
+	return c.conf.GetString(key)
+}
+
+// getSecret converts the resolved value of a secret key with conv, and
+// delegates to get for all other keys.
+func getSecret[T any](c *Secrets, key string, conv func(any) (T, error), get func(string) T) T {
+	if s, ok := c.secret(key); ok {
+		x, _ := conv(s)
+		return x
+	}
+	return get(key)
+}
+
+// lookupSecret converts the resolved value of a secret key with conv, and
+// delegates to lookup for all other keys. Conversion errors neither carry the
+// value nor the cause, which may quote it.
+func lookupSecret[T any](c *Secrets, key string, conv func(any) (T, error), lookup func(string) (T, error)) (T, error) {
+	if !c.IsSecret(key) {
+		return lookup(key)
+	}
+	var zero T
+	typename := fmt.Sprintf("%T", zero)
+	s, err := c.LookupSecret(key)
+	if err != nil {
+		var lerr *LookupError
+		if errors.As(err, &lerr) {
+			lerr.Type = typename
+		}
+		return zero, err
+	}
+	x, err := conv(s.Reveal())
+	if err != nil {
+		return zero, &LookupError{Key: key, Type: typename, Value: Redacted, Err: ErrInvalidValue}
+	}
+	return x, nil
+}
+
+// GetInt returns a configuration property as an integer. Values of secret
+// keys are resolved.
+func (c *Secrets) GetInt(key string) int {
+	return getSecret(c, key, ToInt, c.conf.GetInt)
+}
+
+// GetBool returns a configuration property as a boolean value. Values of
+// secret keys are resolved.
+func (c *Secrets) GetBool(key string) bool {
+	return getSecret(c, key, ToBool, c.conf.GetBool)
+}
+
+// GetFloat64 returns a configuration property as a float. Values of secret
+// keys are resolved.
+func (c *Secrets) GetFloat64(key string) float64 {
+	return getSecret(c, key, ToFloat64, Typed(c.conf).GetFloat64)
+}
+
+// GetDuration returns a configuration property as a time.Duration. Values of
+// secret keys are resolved.
+func (c *Secrets) GetDuration(key string) time.Duration {
+	return getSecret(c, key, ToDuration, Typed(c.conf).GetDuration)
+}
+
+// GetTime returns a configuration property as a time.Time. Values of secret
+// keys are resolved.
+func (c *Secrets) GetTime(key string) time.Time {
+	return getSecret(c, key, ToTime, Typed(c.conf).GetTime)
+}
+
+// GetStringSlice returns a configuration property as a list of strings.
+// Values of secret keys are resolved.
+func (c *Secrets) GetStringSlice(key string) []string {
+	return getSecret(c, key, ToStringSlice, Typed(c.conf).GetStringSlice)
+}
+
+// GetStringMap returns a configuration property as a dictionary. Values of
+// secret keys are resolved.
+func (c *Secrets) GetStringMap(key string) map[string]any {
+	return getSecret(c, key, ToStringMap, Typed(c.conf).GetStringMap)
+}
+
+// LookupString returns a configuration property as a string, or an error
+// if the key is not set or the value cannot be converted. Values of secret
+// keys are resolved.
+func (c *Secrets) LookupString(key string) (string, error) {
+	return lookupSecret(c, key, ToString, Strict(c.conf).LookupString)
+}
+
+// LookupInt returns a configuration property as an integer, or an error
+// if the key is not set or the value cannot be converted. Values of secret
+// keys are resolved.
+func (c *Secrets) LookupInt(key string) (int, error) {
+	return lookupSecret(c, key, ToInt, Strict(c.conf).LookupInt)
+}
+
+// LookupBool returns a configuration property as a boolean value, or an error
+// if the key is not set or the value cannot be converted. Values of secret
+// keys are resolved.
+func (c *Secrets) LookupBool(key string) (bool, error) {
+	return lookupSecret(c, key, ToBool, Strict(c.conf).LookupBool)
+}
+
+// LookupFloat64 returns a configuration property as a float, or an error
+// if the key is not set or the value cannot be converted. Values of secret
+// keys are resolved.
+func (c *Secrets) LookupFloat64(key string) (float64, error) {
+	return lookupSecret(c, key, ToFloat64, Strict(c.conf).LookupFloat64)
+}
+
+// LookupDuration returns a configuration property as a time.Duration, or an error
+// if the key is not set or the value cannot be converted. Values of secret
+// keys are resolved.
+func (c *Secrets) LookupDuration(key string) (time.Duration, error) {
+	return lookupSecret(c, key, ToDuration, Strict(c.conf).LookupDuration)
+}
+
+// LookupTime returns a configuration property as a time.Time, or an error
+// if the key is not set or the value cannot be converted. Values of secret
+// keys are resolved.
+func (c *Secrets) LookupTime(key string) (time.Time, error) {
+	return lookupSecret(c, key, ToTime, Strict(c.conf).LookupTime)
+}
+
+// LookupStringSlice returns a configuration property as a list of strings, or an error
+// if the key is not set or the value cannot be converted. Values of secret
+// keys are resolved.
+func (c *Secrets) LookupStringSlice(key string) ([]string, error) {
+	return lookupSecret(c, key, ToStringSlice, Strict(c.conf).LookupStringSlice)
+}
+
+// LookupStringMap returns a configuration property as a dictionary, or an error
+// if the key is not set or the value cannot be converted. Values of secret
+// keys are resolved.
+func (c *Secrets) LookupStringMap(key string) (map[string]any, error) {
+	return lookupSecret(c, key, ToStringMap, Strict(c.conf).LookupStringMap)
+}
+
+// Origin returns the origin of the value for key, as reported by the
+// underlying configuration.
+func (c *Secrets) Origin(key string) (Origin, bool) {
+	if !c.conf.IsSet(key) {
+		return Origin{}, false
+	}
+	return originOf(c.conf, key), true
+}
+
+// Origins returns the origins reported by the underlying configuration, if it
+// implements ProvenanceConfiguration.
+func (c *Secrets) Origins() map[string]Origin {
+	if pc, ok := c.conf.(ProvenanceConfiguration); ok {
+		return pc.Origins()
+	}
+	return map[string]Origin{}
+}
+
+// Keys returns the keys of the underlying configuration, if it implements
+// EnumerableConfiguration.
+func (c *Secrets) Keys(prefix string) []string {
+	if ec, ok := c.conf.(EnumerableConfiguration); ok {
+		return ec.Keys(prefix)
+	}
+	return nil
+}
+
+// AllSettings returns all settings of the underlying configuration, if it
+// implements EnumerableConfiguration. References to secrets are not resolved;
+// use Redact before dumping settings.
+func (c *Secrets) AllSettings() map[string]any {
+	if ec, ok := c.conf.(EnumerableConfiguration); ok {
+		return ec.AllSettings()
+	}
+	return map[string]any{}
+}
+
+var _ TypedConfiguration = &Secrets{}
+var _ StrictConfiguration = &Secrets{}
+var _ ProvenanceConfiguration = &Secrets{}
+var _ EnumerableConfiguration = &Secrets{}
+var _ SecretConfiguration = &Secrets{}
@@ -0,0 +1,185 @@
+package schuko_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/testconfig"
+)
+
+func TestSecretDoesNotReveal(t *testing.T) {
+	s := schuko.Secret("geheim")
+	for _, out := range []string{fmt.Sprint(s), fmt.Sprintf("%v %s %#v", s, s, s),
+		fmt.Sprintf("%+v", struct{ PW schuko.Secret }{s})} {
+		if strings.Contains(out, "geheim") {
+			t.Errorf("expected secret to be masked, got %q", out)
+		}
+	}
+	if s.Reveal() != "geheim" {
+		t.Errorf("expected Reveal to return plain text, got %q", s.Reveal())
+	}
+}
+
+func TestIsSecretKey(t *testing.T) {
+	for key, secret := range map[string]bool{
+		"db.password":       true,
+		"db/Password":       true,
+		"github.api_token":  true,
+		"aws.secret":        true,
+		"db.host":           false,
+		"password.minimum":  false,
+		"tracing.adapter":   false,
+		"service.apiKey":    true,
+		"tls.private_key":   true,
+		"oauth.credentials": true,
+	} {
+		if schuko.IsSecretKey(key) != secret {
+			t.Errorf("expected IsSecretKey(%q) = %v", key, secret)
+		}
+	}
+}
+
+func TestSecretsResolve(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "db_password")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SECRETTEST_TOKEN", "from-env")
+	conf := schuko.WithSecrets(testconfig.Conf{
+		"db.password": "file://" + path,
+		"api.token":   "env:SECRETTEST_TOKEN",
+		"db.pin":      "1234",
+		"db.missing":  "file://" + filepath.Join(dir, "nope"),
+		"db.host":     "file://not-a-secret",
+		"smtp.secret": "literal",
+	}, schuko.NewSchema(
+		schuko.Setting("db.pin", schuko.TypeString, schuko.Sensitive()),
+		schuko.Setting("db.missing", schuko.TypeString, schuko.Sensitive()),
+	))
+	if pw := conf.GetString("db.password"); pw != "from-file" {
+		t.Errorf("expected password from file, got %q", pw)
+	}
+	if tok := conf.GetString("api.token"); tok != "from-env" {
+		t.Errorf("expected token from environment, got %q", tok)
+	}
+	if !conf.IsSecret("db.pin") || conf.GetInt("db.pin") != 1234 {
+		t.Errorf("expected db.pin to be secret by schema")
+	}
+	if h := conf.GetString("db.host"); h != "file://not-a-secret" {
+		t.Errorf("expected non-secret value to be taken literally, got %q", h)
+	}
+	if s := conf.GetString("smtp.secret"); s != "literal" {
+		t.Errorf("expected literal secret, got %q", s)
+	}
+	if s := conf.GetString("db.missing"); s != "" {
+		t.Errorf("expected unresolved secret to be empty, got %q", s)
+	}
+	_, err := conf.LookupSecret("db.missing")
+	var serr *schuko.SecretError
+	if !errors.As(err, &serr) || !errors.Is(err, schuko.ErrSecretUnresolved) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected unresolved secret to report a SecretError, got %v", err)
+	}
+	if s, err := conf.LookupSecret("db.password"); err != nil || s.Reveal() != "from-file" {
+		t.Errorf("expected LookupSecret to resolve password, got %v", err)
+	}
+}
+
+func TestSecretsTyped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db_timeout")
+	if err := os.WriteFile(path, []byte("1.5s\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SECRETTEST_PORT", "5432")
+	t.Setenv("SECRETTEST_RATIO", "0.25")
+	t.Setenv("SECRETTEST_PIN", "12x")
+	conf := schuko.WithSecrets(testconfig.Conf{
+		"db.port":    "env:SECRETTEST_PORT",
+		"db.ratio":   "env:SECRETTEST_RATIO",
+		"db.timeout": "file://" + path,
+		"db.pin":     "env:SECRETTEST_PIN",
+		"db.host":    "localhost",
+	}, schuko.NewSchema(
+		schuko.Setting("db.port", schuko.TypeInt, schuko.Sensitive()),
+		schuko.Setting("db.ratio", schuko.TypeFloat, schuko.Sensitive()),
+		schuko.Setting("db.timeout", schuko.TypeDuration, schuko.Sensitive()),
+		schuko.Setting("db.pin", schuko.TypeInt, schuko.Sensitive()),
+		schuko.Setting("db.unset", schuko.TypeInt, schuko.Sensitive()),
+	))
+	if p := conf.GetInt("db.port"); p != 5432 {
+		t.Errorf("expected GetInt to resolve secret port, got %d", p)
+	}
+	if p, err := conf.LookupInt("db.port"); err != nil || p != 5432 {
+		t.Errorf("expected LookupInt to resolve secret port, got %d, %v", p, err)
+	}
+	if r := conf.GetFloat64("db.ratio"); r != 0.25 {
+		t.Errorf("expected GetFloat64 to resolve secret ratio, got %v", r)
+	}
+	if r, err := conf.LookupFloat64("db.ratio"); err != nil || r != 0.25 {
+		t.Errorf("expected LookupFloat64 to resolve secret ratio, got %v, %v", r, err)
+	}
+	if d := conf.GetDuration("db.timeout"); d != 1500*time.Millisecond {
+		t.Errorf("expected GetDuration to resolve secret timeout, got %v", d)
+	}
+	if d, err := conf.LookupDuration("db.timeout"); err != nil || d != 1500*time.Millisecond {
+		t.Errorf("expected LookupDuration to resolve secret timeout, got %v, %v", d, err)
+	}
+	if h, err := conf.LookupString("db.host"); err != nil || h != "localhost" {
+		t.Errorf("expected non-secret key to be looked up unchanged, got %q, %v", h, err)
+	}
+	_, err := conf.LookupInt("db.pin")
+	if !errors.Is(err, schuko.ErrInvalidValue) || strings.Contains(err.Error(), "12x") {
+		t.Errorf("expected invalid secret not to be revealed, got %v", err)
+	}
+	_, err = conf.LookupInt("db.unset")
+	var lerr *schuko.LookupError
+	if !errors.As(err, &lerr) || lerr.Type != "int" || !errors.Is(err, schuko.ErrKeyNotFound) {
+		t.Errorf("expected unset secret to be reported as missing int, got %v", err)
+	}
+}
+
+func TestSecretsRedacted(t *testing.T) {
+	conf := schuko.WithSecrets(testconfig.Conf{
+		"db.password": "geheim",
+		"db.user":     "admin",
+		"db.pin":      "1234",
+	}, schuko.NewSchema(schuko.Setting("db.pin", schuko.TypeString, schuko.Sensitive())))
+	for _, key := range []string{"db.password", "db.pin"} {
+		if e := schuko.Explain(conf, key); e.Value != schuko.Redacted || strings.Contains(e.String(), "geheim") {
+			t.Errorf("expected explanation of %s to be redacted, got %v", key, e)
+		}
+	}
+	if e := schuko.Explain(conf, "db.user"); e.Value != "admin" {
+		t.Errorf("expected explanation of db.user to show value, got %v", e)
+	}
+	m := schuko.Redact(conf, conf.AllSettings())
+	db := m["db"].(map[string]any)
+	if db["password"] != schuko.Redacted || db["pin"] != schuko.Redacted || db["user"] != "admin" {
+		t.Errorf("expected secret settings to be redacted, got %v", m)
+	}
+	layered := schuko.NewLayered(schuko.Layer{Name: "conf", Conf: conf})
+	if e := schuko.Explain(schuko.Sub(layered, "db"), "pin"); e.Value != schuko.Redacted {
+		t.Errorf("expected secret to be redacted through views, got %v", e)
+	}
+}
+
+func TestValidateDoesNotRevealSecrets(t *testing.T) {
+	conf := testconfig.Conf{"db.password": "geheim", "db.pin": "12x"}
+	schema := schuko.NewSchema(
+		schuko.Setting("db.password", schuko.TypeString, schuko.Matches(`^.{8,}$`)),
+		schuko.Setting("db.pin", schuko.TypeInt, schuko.Sensitive()),
+	)
+	_, err := schema.Validate(conf)
+	if err == nil {
+		t.Fatal("expected validation to fail")
+	}
+	if strings.Contains(err.Error(), "geheim") || strings.Contains(err.Error(), "12x") {
+		t.Errorf("expected violations not to reveal secrets, got %q", err)
+	}
+}
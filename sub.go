@@ -0,0 +1,260 @@
+package schuko
+
+import (
+	"strings"
+	"time"
+)
+
+// SubConfiguration is a view on a configuration, scoped to a key prefix.
+// It is intended for libraries, which may receive “their” part of the
+// application configuration without knowing the overall key layout:
+//
+//	db.Connect(schuko.Sub(conf, "db"))  // db.Connect will look up "host", "port", etc.
+//
+// A SubConfiguration holds no values of its own, i.e. it reflects any later
+// changes of the parent configuration.
+type SubConfiguration struct {
+	parent Configuration
+	prefix string
+}
+
+// Sub creates a view on conf, scoped to prefix. Keys are translated by
+// prepending prefix, separated by a dot. As with trace2go, a slash is
+// tolerated as a separator as well: for prefix "db", key "host" will be
+// looked up as "db.host" and then as "db/host".
+func Sub(conf Configuration, prefix string) *SubConfiguration {
+	return &SubConfiguration{
+		parent: conf,
+		prefix: strings.TrimRight(prefix, "./"),
+	}
+}
+
+// Parent returns the configuration this view is scoped on.
+func (s *SubConfiguration) Parent() Configuration {
+	return s.parent
+}
+
+// Prefix returns the key prefix of this view.
+func (s *SubConfiguration) Prefix() string {
+	return s.prefix
+}
+
+// Key translates a key of this view to the corresponding key of the parent
+// configuration. If the key is not set with any of the separator variants,
+// the dotted variant is returned.
+func (s *SubConfiguration) Key(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	dotted := s.prefix + "." + key
+	if s.parent.IsSet(dotted) {
+		return dotted
+	}
+	if slashed := s.prefix + "/" + key; s.parent.IsSet(slashed) {
+		return slashed
+	}
+	return dotted
+}
+
+// Keys returns the keys of all values within this view matching prefix,
+// relative to the prefix of the view and in sorted order. If the parent does
+// not implement EnumerableConfiguration, keys are collected from the origins
+// of the parent (if it implements ProvenanceConfiguration) and from the
+// dictionary found at the prefix of the view.
+func (s *SubConfiguration) Keys(prefix string) []string {
+	seen := make(map[string]bool)
+	if ec, ok := s.parent.(EnumerableConfiguration); ok {
+		for _, key := range ec.Keys(s.prefix) {
+			if rel, ok := s.relative(key); ok {
+				seen[rel] = true
+			}
+		}
+	} else {
+		if pc, ok := s.parent.(ProvenanceConfiguration); ok {
+			for key := range pc.Origins() {
+				if rel, ok := s.relative(key); ok {
+					seen[rel] = true
+				}
+			}
+		}
+		if s.prefix != "" {
+			flattenKeys(Typed(s.parent).GetStringMap(s.prefix), "", seen)
+		}
+	}
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	return FilterKeys(keys, prefix)
+}
+
+// AllSettings returns all values within this view as a nested dictionary.
+func (s *SubConfiguration) AllSettings() map[string]any {
+	if s.prefix == "" {
+		if ec, ok := s.parent.(EnumerableConfiguration); ok {
+			return ec.AllSettings()
+		}
+	}
+	m := Typed(s.parent).GetStringMap(s.prefix)
+	if m == nil {
+		m = make(map[string]any)
+	}
+	return m
+}
+
+// relative returns a key of the parent relative to the prefix, if the key is
+// within this view.
+func (s *SubConfiguration) relative(key string) (string, bool) {
+	if s.prefix == "" {
+		return key, true
+	}
+	for _, sep := range []string{".", "/"} {
+		if rel, ok := strings.CutPrefix(key, s.prefix+sep); ok && rel != "" {
+			return rel, true
+		}
+	}
+	return "", false
+}
+
+// flattenKeys collects the keys of all leaf values of a nested dictionary,
+// joining the keys of nested dictionaries with dots.
+func flattenKeys(m map[string]any, parentKey string, keys map[string]bool) {
+	for k, v := range m {
+		if parentKey != "" {
+			k = parentKey + "." + k
+		}
+		if sub, ok := v.(map[string]any); ok {
+			flattenKeys(sub, k, keys)
+			continue
+		}
+		keys[k] = true
+	}
+}
+
+// InitDefaults does nothing. Initializing the parent configuration is up to
+// its owner.
+func (s *SubConfiguration) InitDefaults() {}
+
+// IsSet is a predicate wether a key is set within this view.
+func (s *SubConfiguration) IsSet(key string) bool {
+	return s.parent.IsSet(s.Key(key))
+}
+
+// GetString returns a configuration property as a string.
+func (s *SubConfiguration) GetString(key string) string {
+	return s.parent.GetString(s.Key(key))
+}
+
+// GetInt returns a configuration property as an integer.
+func (s *SubConfiguration) GetInt(key string) int {
+	return s.parent.GetInt(s.Key(key))
+}
+
+// GetBool returns a configuration property as a boolean value.
+func (s *SubConfiguration) GetBool(key string) bool {
+	return s.parent.GetBool(s.Key(key))
+}
+
+// GetFloat64 returns a configuration property as a float.
+func (s *SubConfiguration) GetFloat64(key string) float64 {
+	return Typed(s.parent).GetFloat64(s.Key(key))
+}
+
+// GetDuration returns a configuration property as a time.Duration.
+func (s *SubConfiguration) GetDuration(key string) time.Duration {
+	return Typed(s.parent).GetDuration(s.Key(key))
+}
+
+// GetTime returns a configuration property as a time.Time.
+func (s *SubConfiguration) GetTime(key string) time.Time {
+	return Typed(s.parent).GetTime(s.Key(key))
+}
+
+// GetStringSlice returns a configuration property as a list of strings.
+func (s *SubConfiguration) GetStringSlice(key string) []string {
+	return Typed(s.parent).GetStringSlice(s.Key(key))
+}
+
+// GetStringMap returns a configuration property as a dictionary.
+func (s *SubConfiguration) GetStringMap(key string) map[string]any {
+	return Typed(s.parent).GetStringMap(s.Key(key))
+}
+
+// LookupString returns a configuration property as a string, or an error
+// if the key is not set or the value cannot be converted.
+func (s *SubConfiguration) LookupString(key string) (string, error) {
+	return Strict(s.parent).LookupString(s.Key(key))
+}
+
+// LookupInt returns a configuration property as an integer, or an error
+// if the key is not set or the value cannot be converted.
+func (s *SubConfiguration) LookupInt(key string) (int, error) {
+	return Strict(s.parent).LookupInt(s.Key(key))
+}
+
+// LookupBool returns a configuration property as a boolean value, or an error
+// if the key is not set or the value cannot be converted.
+func (s *SubConfiguration) LookupBool(key string) (bool, error) {
+	return Strict(s.parent).LookupBool(s.Key(key))
+}
+
+// LookupFloat64 returns a configuration property as a float, or an error
+// if the key is not set or the value cannot be converted.
+func (s *SubConfiguration) LookupFloat64(key string) (float64, error) {
+	return Strict(s.parent).LookupFloat64(s.Key(key))
+}
+
+// LookupDuration returns a configuration property as a time.Duration, or an error
+// if the key is not set or the value cannot be converted.
+func (s *SubConfiguration) LookupDuration(key string) (time.Duration, error) {
+	return Strict(s.parent).LookupDuration(s.Key(key))
+}
+
+// LookupTime returns a configuration property as a time.Time, or an error
+// if the key is not set or the value cannot be converted.
+func (s *SubConfiguration) LookupTime(key string) (time.Time, error) {
+	return Strict(s.parent).LookupTime(s.Key(key))
+}
+
+// LookupStringSlice returns a configuration property as a list of strings, or an error
+// if the key is not set or the value cannot be converted.
+func (s *SubConfiguration) LookupStringSlice(key string) ([]string, error) {
+	return Strict(s.parent).LookupStringSlice(s.Key(key))
+}
+
+// LookupStringMap returns a configuration property as a dictionary, or an error
+// if the key is not set or the value cannot be converted.
+func (s *SubConfiguration) LookupStringMap(key string) (map[string]any, error) {
+	return Strict(s.parent).LookupStringMap(s.Key(key))
+}
+
+// Origin returns the origin of the value for key, as reported by the parent.
+func (s *SubConfiguration) Origin(key string) (Origin, bool) {
+	full := s.Key(key)
+	if !s.parent.IsSet(full) {
+		return Origin{}, false
+	}
+	return originOf(s.parent, full), true
+}
+
+// Origins returns the origins of all keys within this view, relative to the prefix.
+func (s *SubConfiguration) Origins() map[string]Origin {
+	origins := make(map[string]Origin)
+	for _, key := range s.Keys("") {
+		if o, found := s.Origin(key); found {
+			origins[key] = o
+		}
+	}
+	return origins
+}
+
+// IsSecret is a predicate wether the parent considers the value of key secret.
+func (s *SubConfiguration) IsSecret(key string) bool {
+	return IsSecret(s.parent, s.Key(key))
+}
+
+var _ TypedConfiguration = &SubConfiguration{}
+var _ StrictConfiguration = &SubConfiguration{}
+var _ ProvenanceConfiguration = &SubConfiguration{}
+var _ EnumerableConfiguration = &SubConfiguration{}
+var _ SecretConfiguration = &SubConfiguration{}
@@ -0,0 +1,51 @@
+package schuko_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/testconfig"
+)
+
+func TestSubTranslatesKeys(t *testing.T) {
+	conf := testconfig.Conf{
+		"db.host":       "db.local",
+		"db/port":       5432,
+		"db.name":       "app",
+		"db.pool.size":  "10",
+		"server.port":   8080,
+		"database.host": "other",
+	}
+	db := schuko.Sub(conf, "db")
+	if h := db.GetString("host"); h != "db.local" {
+		t.Errorf("expected host = db.local, got %q", h)
+	}
+	if p := db.GetInt("port"); p != 5432 {
+		t.Errorf("expected port from slashed key, got %d", p)
+	}
+	if db.IsSet("server.port") {
+		t.Errorf("expected keys outside of view to be invisible")
+	}
+	if n := schuko.Sub(db, "pool").GetInt("size"); n != 10 {
+		t.Errorf("expected nested view to find pool.size, got %d", n)
+	}
+	if keys := db.Keys(""); !reflect.DeepEqual(keys, []string{"host", "name", "pool.size", "port"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	conf["db.user"] = "admin"
+	if u := db.GetString("user"); u != "admin" {
+		t.Errorf("expected view to reflect changes of parent, got user = %q", u)
+	}
+}
+
+func TestSubOrigins(t *testing.T) {
+	conf := schuko.NewLayered(schuko.Layer{Name: "test", Conf: testconfig.Conf{}})
+	sub := schuko.Sub(conf, "db.")
+	if sub.Prefix() != "db" {
+		t.Errorf("expected trailing separator to be trimmed, got prefix %q", sub.Prefix())
+	}
+	if _, found := sub.Origin("host"); found {
+		t.Errorf("expected no origin for unset key")
+	}
+}
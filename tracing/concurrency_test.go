@@ -0,0 +1,142 @@
+package tracing_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/npillmayer/schuko/tracing"
+	"github.com/npillmayer/schuko/tracing/gologadapter"
+	"github.com/npillmayer/schuko/tracing/goslogadapter"
+	"github.com/npillmayer/schuko/tracing/gotestingadapter"
+	"github.com/npillmayer/schuko/tracing/logrusadapter"
+)
+
+// Run with `go test -race` to let the race detector check the adapters.
+
+var adapters = map[string]tracing.Adapter{
+	"go":       gologadapter.GetAdapter(),
+	"slog":     goslogadapter.GetAdapter(),
+	"slogjson": goslogadapter.GetJSONAdapter(),
+	"logrus":   logrusadapter.GetAdapter(),
+}
+
+func TestPDoesNotModifyTracer(t *testing.T) {
+	for name, adapter := range adapters {
+		t.Run(name, func(t *testing.T) {
+			tr := adapter()
+			buf := &bytes.Buffer{}
+			tr.SetOutput(buf)
+			tr.SetTraceLevel(tracing.LevelInfo)
+			base := tr.P("base", "b0")
+			base.P("left", "l0")
+			right := base.P("right", "r0")
+			base.P("other", "o0")
+			tr.Infof("plain")
+			base.Infof("based")
+			right.Infof("derived")
+			right.Infof("derived again")
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != 4 {
+				t.Fatalf("expected 4 lines of output, got %q", lines)
+			}
+			expect := [][]string{{}, {"b0"}, {"b0", "r0"}, {"b0", "r0"}}
+			for i, line := range lines {
+				for _, v := range []string{"b0", "l0", "r0", "o0"} {
+					want := false
+					for _, w := range expect[i] {
+						want = want || v == w
+					}
+					if strings.Contains(line, v) != want {
+						t.Errorf("line %d: expected field %s present=%v, got %q", i, v, want, line)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestConcurrentFields(t *testing.T) {
+	const goroutines, messages = 16, 50
+	value := regexp.MustCompile(`v\d{3}`)
+	for name, adapter := range adapters {
+		t.Run(name, func(t *testing.T) {
+			tr := adapter()
+			buf := &bytes.Buffer{}
+			tr.SetOutput(buf)
+			tr.SetTraceLevel(tracing.LevelDebug)
+			shared := tr.P("shared", "s")
+			var wg sync.WaitGroup
+			for i := range goroutines {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					tg := shared.P("g", fmt.Sprintf("v%03d", i))
+					for j := range messages {
+						tg.P("j", j).Debugf("m%03d", i)
+						tg.Infof("m%03d", i)
+					}
+				}()
+			}
+			wg.Wait()
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != 2*goroutines*messages {
+				t.Fatalf("expected %d lines of output, got %d", 2*goroutines*messages, len(lines))
+			}
+			for _, line := range lines {
+				m := regexp.MustCompile(`m(\d{3})`).FindStringSubmatch(line)
+				vs := value.FindAllString(line, -1)
+				if m == nil || len(vs) != 1 || vs[0] != "v"+m[1] {
+					t.Fatalf("fields of other goroutines attached to message: %q", line)
+				}
+			}
+		})
+	}
+}
+
+// captureTB captures the output of Logf, which testing.T does not expose.
+type captureTB struct {
+	testing.TB
+	mx    sync.Mutex
+	lines []string
+}
+
+func (c *captureTB) Logf(format string, args ...any) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+	c.lines = append(c.lines, fmt.Sprintf(format, args...))
+}
+
+func TestConcurrentTestingTracer(t *testing.T) {
+	const goroutines, messages = 4, 3
+	ctb := &captureTB{TB: t}
+	tr := gotestingadapter.New(ctb)
+	tr.SetTraceLevel(tracing.LevelDebug)
+	shared := tr.P("shared", "s")
+	var wg sync.WaitGroup
+	for i := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tg := shared.P("g", i)
+			for j := range messages {
+				tg.P("j", j).Debugf("message of goroutine %d", i)
+			}
+		}()
+	}
+	tr.SetTraceLevel(tracing.LevelTrace)
+	wg.Wait()
+	if len(ctb.lines) != goroutines*messages {
+		t.Fatalf("expected %d lines of output, got %q", goroutines*messages, ctb.lines)
+	}
+	for _, line := range ctb.lines {
+		m := regexp.MustCompile(`goroutine (\d)`).FindStringSubmatch(line)
+		if m == nil || strings.Count(line, "[shared=s]") != 1 || strings.Count(line, "[j=") != 1 ||
+			strings.Count(line, "[g=") != 1 || !strings.Contains(line, "[g="+m[1]+"]") {
+			t.Errorf("fields of other goroutines attached to message: %q", line)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package gologadapter_test
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/npillmayer/schuko/tracing"
@@ -16,3 +18,37 @@ func Test1(t *testing.T) {
 	l.SetTraceLevel(tracing.LevelError)
 	l.Debugf("Hello 3")
 }
+
+func TestStructuredEvents(t *testing.T) {
+	l := tracing.Structured(gologadapter.New())
+	buf := &bytes.Buffer{}
+	l.SetOutput(buf)
+	l.SetTraceLevel(tracing.LevelInfo)
+	l.Debug("filtered", "key", "k0")
+	l.Info("cache miss", "key", "users/42", "size", 1024)
+	tracing.Structured(l.P("req", 7)).Warn("slow", "reason", "lock wait")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines of output, got %q", lines)
+	}
+	if !strings.HasPrefix(lines[0], "INFO ") || !strings.HasSuffix(lines[0], " cache miss key=users/42 size=1024") {
+		t.Errorf("expected attributes appended to message, got %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "WARN ") || !strings.HasSuffix(lines[1], ` [req=7] slow reason="lock wait"`) {
+		t.Errorf("expected field prefix and attributes, got %q", lines[1])
+	}
+}
+
+func TestLevelOutOfRange(t *testing.T) {
+	l := gologadapter.New()
+	buf := &bytes.Buffer{}
+	l.SetOutput(buf)
+	l.SetTraceLevel(tracing.TraceLevel(7))
+	if l.GetTraceLevel() != tracing.TraceLevel(7) {
+		t.Errorf("expected level to be kept, got %v", l.GetTraceLevel())
+	}
+	tracing.Leveled(l).Tracef("Hello")
+	if !strings.HasPrefix(buf.String(), "TRACE ") {
+		t.Errorf("expected trace output, got %q", buf.String())
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"sync"
 
 	"github.com/npillmayer/schuko/tracing"
 )
@@ -29,16 +30,31 @@ import (
 // Tracer is our adapter implementation which implements interface
 // tracing.Trace, using a Go standard logger.
 type Tracer struct {
+	mx    sync.Mutex // guards level and output, as the level prefix is switched per message
 	log   *log.Logger
 	level tracing.TraceLevel
 }
 
-var logLevelPrefix = []string{"ERROR ", "INFO  ", "DEBUG "}
+// levelPrefix returns the message prefix for trace level l. Levels beyond
+// tracing.LevelTrace are clamped to it.
+func levelPrefix(l tracing.TraceLevel) string {
+	switch l {
+	case tracing.LevelError:
+		return "ERROR "
+	case tracing.LevelWarn:
+		return "WARN  "
+	case tracing.LevelInfo:
+		return "INFO  "
+	case tracing.LevelDebug:
+		return "DEBUG "
+	}
+	return "TRACE "
+}
 
 // New creates a new Tracer instance based on a Go logger.
 func New() tracing.Trace {
 	return &Tracer{
-		log:   log.New(os.Stderr, logLevelPrefix[0], log.Ltime),
+		log:   log.New(os.Stderr, levelPrefix(tracing.LevelError), log.Ltime),
 		level: tracing.LevelError,
 	}
 }
@@ -53,43 +69,67 @@ func GetAdapter() tracing.Adapter {
 
 // P is part of interface Trace
 func (t *Tracer) P(key string, val any) tracing.Trace {
-	l := &logentry{tracer: t}
-	l.p = fmt.Sprintf("[%s=%v] ", key, val)
-	return l
+	return &logentry{tracer: t, p: field(key, val)}
 }
 
 // Debugf is part of interface Trace
 func (t *Tracer) Debugf(s string, args ...any) {
-	if t.level < tracing.LevelDebug {
-		return
-	}
 	t.output(tracing.LevelDebug, "", s, args...)
 }
 
 // Infof is part of interface Trace
 func (t *Tracer) Infof(s string, args ...any) {
-	if t.level < tracing.LevelInfo {
-		return
-	}
 	t.output(tracing.LevelInfo, "", s, args...)
 }
 
+// Warnf is part of interface LevelTracer
+func (t *Tracer) Warnf(s string, args ...any) {
+	t.output(tracing.LevelWarn, "", s, args...)
+}
+
+// Tracef is part of interface LevelTracer
+func (t *Tracer) Tracef(s string, args ...any) {
+	t.output(tracing.LevelTrace, "", s, args...)
+}
+
 // Errorf is part of interface Trace
 func (t *Tracer) Errorf(s string, args ...any) {
-	if t.level < tracing.LevelError {
-		return
-	}
 	t.output(tracing.LevelError, "", s, args...)
 }
 
+// Error is part of interface StructuredTracer. This tracer appends attributes
+// to msg (see tracing.FormatAttrs).
+func (t *Tracer) Error(msg string, kv ...any) {
+	t.output(tracing.LevelError, "", "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+// Warn is part of interface StructuredTracer
+func (t *Tracer) Warn(msg string, kv ...any) {
+	t.output(tracing.LevelWarn, "", "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+// Info is part of interface StructuredTracer
+func (t *Tracer) Info(msg string, kv ...any) {
+	t.output(tracing.LevelInfo, "", "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+// Debug is part of interface StructuredTracer
+func (t *Tracer) Debug(msg string, kv ...any) {
+	t.output(tracing.LevelDebug, "", "%s", tracing.FormatAttrs(msg, kv...))
+}
+
 // SetTraceLevel is part of interface Trace
 func (t *Tracer) SetTraceLevel(l tracing.TraceLevel) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
 	t.level = l
-	t.log.SetPrefix(logLevelPrefix[int(l)])
+	t.log.SetPrefix(levelPrefix(l))
 }
 
 // GetTraceLevel is part of interface Trace
 func (t *Tracer) GetTraceLevel() tracing.TraceLevel {
+	t.mx.Lock()
+	defer t.mx.Unlock()
 	return t.level
 }
 
@@ -99,59 +139,86 @@ func (t *Tracer) SetOutput(writer io.Writer) {
 }
 
 func (t *Tracer) output(l tracing.TraceLevel, p string, s string, args ...any) {
-	t.log.SetPrefix(logLevelPrefix[int(l)])
+	t.mx.Lock()
+	defer t.mx.Unlock()
+	if t.level < l {
+		return
+	}
+	t.log.SetPrefix(levelPrefix(l))
 	if p == "" { // if no prefix present
 		t.log.Printf(s, args...)
 	} else {
-		t.log.Println(fmt.Sprintf(p+s, args...))
+		t.log.Println(p + fmt.Sprintf(s, args...))
 	}
 }
 
 // ----------------------------------------------------------------------------
 
-// logentry is a helper for prefixed tracing
+// logentry is a helper for prefixed tracing. Fields are accumulated as a
+// prefix for trace messages; logentries are never modified after creation.
 type logentry struct { // will have to implement tracing.Trace
 	tracer *Tracer // tracer where this logentry will go
 	p      string  // prefix
 }
 
 func (l *logentry) Debugf(s string, args ...any) {
-	if l.tracer.level < tracing.LevelDebug {
-		return
-	}
-	l.tracer.output(tracing.LevelDebug, "", s, args...)
+	l.tracer.output(tracing.LevelDebug, l.p, s, args...)
 }
 
 func (l *logentry) Infof(s string, args ...any) {
-	if l.tracer.level < tracing.LevelInfo {
-		return
-	}
-	l.tracer.output(tracing.LevelDebug, "", s, args...)
+	l.tracer.output(tracing.LevelInfo, l.p, s, args...)
 }
 
 func (l *logentry) Errorf(s string, args ...any) {
-	if l.tracer.level < tracing.LevelError {
-		return
-	}
-	l.tracer.output(tracing.LevelDebug, l.p, s, args...)
+	l.tracer.output(tracing.LevelError, l.p, s, args...)
+}
+
+func (l *logentry) Warnf(s string, args ...any) {
+	l.tracer.output(tracing.LevelWarn, l.p, s, args...)
+}
+
+func (l *logentry) Tracef(s string, args ...any) {
+	l.tracer.output(tracing.LevelTrace, l.p, s, args...)
+}
+
+func (l *logentry) Error(msg string, kv ...any) {
+	l.tracer.output(tracing.LevelError, l.p, "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+func (l *logentry) Warn(msg string, kv ...any) {
+	l.tracer.output(tracing.LevelWarn, l.p, "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+func (l *logentry) Info(msg string, kv ...any) {
+	l.tracer.output(tracing.LevelInfo, l.p, "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+func (l *logentry) Debug(msg string, kv ...any) {
+	l.tracer.output(tracing.LevelDebug, l.p, "%s", tracing.FormatAttrs(msg, kv...))
 }
 
 func (l *logentry) P(key string, val any) tracing.Trace {
-	var p string
+	return &logentry{tracer: l.tracer, p: l.p + field(key, val)}
+}
+
+// field formats a key/value pair as a prefix for trace messages.
+func field(key string, val any) string {
+	val = tracing.Mask(key, val)
 	switch v := val.(type) {
 	case rune:
-		p = fmt.Sprintf("[%s=%#U] ", key, v)
+		return fmt.Sprintf("[%s=%#U] ", key, v)
 	case int, int8, int16, int64, uint16, uint32, uint64:
-		p = fmt.Sprintf("[%s=%d] ", key, v)
+		return fmt.Sprintf("[%s=%d] ", key, v)
 	case string:
-		p = fmt.Sprintf("[%s=%s] ", key, v)
+		return fmt.Sprintf("[%s=%s] ", key, v)
 	default:
-		p = fmt.Sprintf("[%s=%v] ", key, v)
+		return fmt.Sprintf("[%s=%v] ", key, v)
 	}
-	l.p = l.p + p
-	return l
 }
 
 func (l *logentry) SetTraceLevel(tracing.TraceLevel)  {}
 func (l *logentry) GetTraceLevel() tracing.TraceLevel { return l.tracer.GetTraceLevel() }
 func (l *logentry) SetOutput(writer io.Writer)        {}
+
+var _ tracing.StructuredTracer = &Tracer{}
+var _ tracing.StructuredTracer = &logentry{}
@@ -2,9 +2,11 @@ package goslogadapter_test
 
 import (
 	"bytes"
+	"log/slog"
 	"strings"
 	"testing"
 
+	"github.com/npillmayer/schuko"
 	"github.com/npillmayer/schuko/tracing"
 	"github.com/npillmayer/schuko/tracing/goslogadapter"
 )
@@ -103,3 +105,75 @@ func TestGetAdapter(t *testing.T) {
 	}
 }
 
+func TestJSONOutput(t *testing.T) {
+	l := goslogadapter.NewJSON()
+	buf := &bytes.Buffer{}
+	l.SetOutput(buf)
+	l.SetTraceLevel(tracing.LevelInfo)
+
+	l.P("a", "b").Infof("hello %s", "world")
+	out := buf.String()
+	if !strings.Contains(out, `"msg":"hello world"`) {
+		t.Errorf("expected JSON message in output, got %q", out)
+	}
+	if !strings.Contains(out, `"a":"b"`) {
+		t.Errorf("expected JSON field a in output, got %q", out)
+	}
+}
+
+func TestPMasksSecrets(t *testing.T) {
+	l := goslogadapter.New()
+	buf := &bytes.Buffer{}
+	l.SetOutput(buf)
+	l.SetTraceLevel(tracing.LevelInfo)
+
+	l.P("password", "geheim").P("key", schuko.Secret("s3cret")).Infof("login")
+	out := buf.String()
+	if strings.Contains(out, "geheim") || strings.Contains(out, "s3cret") {
+		t.Errorf("expected secrets to be masked, got %q", out)
+	}
+}
+
+func TestWarnAndTraceLevels(t *testing.T) {
+	l := tracing.Leveled(goslogadapter.New())
+	buf := &bytes.Buffer{}
+	l.SetOutput(buf)
+	l.SetTraceLevel(tracing.LevelWarn)
+	l.Infof("info msg")
+	l.Warnf("warn msg")
+	l.Tracef("trace msg 1")
+	if out := buf.String(); strings.Contains(out, "info msg") || !strings.Contains(out, "level=WARN") {
+		t.Errorf("expected only warning to be logged, got %q", out)
+	}
+	l.SetTraceLevel(tracing.LevelTrace)
+	if lvl := l.GetTraceLevel(); lvl != tracing.LevelTrace {
+		t.Errorf("expected level trace, got %v", lvl)
+	}
+	l.Tracef("trace msg 2")
+	out := buf.String()
+	if strings.Contains(out, "trace msg 1") || !strings.Contains(out, `level=TRACE msg="trace msg 2"`) {
+		t.Errorf("expected trace message to be logged at level TRACE, got %q", out)
+	}
+}
+
+func TestStructuredEvents(t *testing.T) {
+	l := tracing.Structured(goslogadapter.NewJSON())
+	buf := &bytes.Buffer{}
+	l.SetOutput(buf)
+	l.SetTraceLevel(tracing.LevelInfo)
+	l.Debug("filtered", "key", "k0")
+	l.Info("cache miss", "key", "users/42", "size", 1024, "token", "abc")
+	tracing.Structured(l.P("req", 7)).Warn("slow", slog.Group("db", "ms", 250))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines of output, got %q", lines)
+	}
+	for i, want := range []string{
+		`"msg":"cache miss","key":"users/42","size":1024,"token":"******"`,
+		`"level":"WARN","msg":"slow","req":7,"db":{"ms":250}`,
+	} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("expected native attributes %s, got %q", want, lines[i])
+		}
+	}
+}
@@ -32,29 +32,48 @@ import (
 type Tracer struct {
 	log   *slog.Logger
 	level *slog.LevelVar
+	json  bool // use a JSON handler instead of a text handler
 }
 
 // New creates a new Tracer instance based on slog.
 func New() tracing.Trace {
 	lv := &slog.LevelVar{}
 	lv.Set(slog.LevelError)
-	h := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: lv})
 	return &Tracer{
-		log:   slog.New(h),
+		log:   slog.New(newHandler(os.Stderr, lv, false)),
 		level: lv,
 	}
 }
 
+// NewJSON creates a new Tracer instance based on slog, which outputs
+// JSON-formatted log records. This is suited for production environments,
+// where logs are collected by log processors.
+func NewJSON() tracing.Trace {
+	lv := &slog.LevelVar{}
+	lv.Set(slog.LevelError)
+	return &Tracer{
+		log:   slog.New(newHandler(os.Stderr, lv, true)),
+		level: lv,
+		json:  true,
+	}
+}
+
 // GetAdapter creates an adapter (i.e., factory for tracing.Trace) to
 // be used to initialize (global) tracers.
 func GetAdapter() tracing.Adapter {
 	return New
 }
 
+// GetJSONAdapter creates an adapter for tracers with JSON output (see NewJSON).
+func GetJSONAdapter() tracing.Adapter {
+	return NewJSON
+}
+
 // ----------------------------------------------------------------------------
 
 // P is part of interface Trace.
 func (t *Tracer) P(key string, val any) tracing.Trace {
+	val = tracing.Mask(key, val)
 	return &logentry{
 		tracer: t,
 		attrs:  []any{key, val},
@@ -71,11 +90,41 @@ func (t *Tracer) Infof(s string, args ...any) {
 	t.output(tracing.LevelInfo, nil, s, args...)
 }
 
+// Warnf is part of interface LevelTracer.
+func (t *Tracer) Warnf(s string, args ...any) {
+	t.output(tracing.LevelWarn, nil, s, args...)
+}
+
+// Tracef is part of interface LevelTracer.
+func (t *Tracer) Tracef(s string, args ...any) {
+	t.output(tracing.LevelTrace, nil, s, args...)
+}
+
 // Errorf is part of interface Trace.
 func (t *Tracer) Errorf(s string, args ...any) {
 	t.output(tracing.LevelError, nil, s, args...)
 }
 
+// Error is part of interface StructuredTracer.
+func (t *Tracer) Error(msg string, kv ...any) {
+	t.event(tracing.LevelError, nil, msg, kv)
+}
+
+// Warn is part of interface StructuredTracer.
+func (t *Tracer) Warn(msg string, kv ...any) {
+	t.event(tracing.LevelWarn, nil, msg, kv)
+}
+
+// Info is part of interface StructuredTracer.
+func (t *Tracer) Info(msg string, kv ...any) {
+	t.event(tracing.LevelInfo, nil, msg, kv)
+}
+
+// Debug is part of interface StructuredTracer.
+func (t *Tracer) Debug(msg string, kv ...any) {
+	t.event(tracing.LevelDebug, nil, msg, kv)
+}
+
 // SetTraceLevel is part of interface Trace.
 func (t *Tracer) SetTraceLevel(l tracing.TraceLevel) {
 	t.level.Set(translateTraceLevel(l))
@@ -88,8 +137,7 @@ func (t *Tracer) GetTraceLevel() tracing.TraceLevel {
 
 // SetOutput is part of interface Trace.
 func (t *Tracer) SetOutput(writer io.Writer) {
-	h := slog.NewTextHandler(writer, &slog.HandlerOptions{Level: t.level})
-	t.log = slog.New(h)
+	t.log = slog.New(newHandler(writer, t.level, t.json))
 }
 
 func (t *Tracer) output(l tracing.TraceLevel, attrs []any, s string, args ...any) {
@@ -106,12 +154,54 @@ func (t *Tracer) output(l tracing.TraceLevel, attrs []any, s string, args ...any
 	t.log.With(attrs...).Log(ctx, sl, msg)
 }
 
+// event logs a message with attributes kv (see tracing.Attrs) natively.
+func (t *Tracer) event(l tracing.TraceLevel, attrs []any, msg string, kv []any) {
+	sl := translateTraceLevel(l)
+	ctx := context.Background()
+	if !t.log.Enabled(ctx, sl) {
+		return
+	}
+	log := t.log
+	if len(attrs) > 0 {
+		log = log.With(attrs...)
+	}
+	log.LogAttrs(ctx, sl, msg, tracing.Attrs(kv...)...)
+}
+
+// LevelTrace is the slog level used for tracing.LevelTrace. slog has no
+// level finer than debug, so we define one.
+const LevelTrace = slog.LevelDebug - 4
+
+// newHandler creates a slog handler for text or JSON output. Records of level
+// LevelTrace are labeled "TRACE".
+func newHandler(w io.Writer, level *slog.LevelVar, json bool) slog.Handler {
+	opts := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey && len(groups) == 0 {
+				if l, ok := a.Value.Any().(slog.Level); ok && l <= LevelTrace {
+					a.Value = slog.StringValue("TRACE")
+				}
+			}
+			return a
+		},
+	}
+	if json {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
 func translateSlogLevel(l slog.Level) tracing.TraceLevel {
 	switch {
+	case l <= LevelTrace:
+		return tracing.LevelTrace
 	case l <= slog.LevelDebug:
 		return tracing.LevelDebug
-	case l < slog.LevelError:
+	case l <= slog.LevelInfo:
 		return tracing.LevelInfo
+	case l < slog.LevelError:
+		return tracing.LevelWarn
 	default:
 		return tracing.LevelError
 	}
@@ -119,10 +209,14 @@ func translateSlogLevel(l slog.Level) tracing.TraceLevel {
 
 func translateTraceLevel(l tracing.TraceLevel) slog.Level {
 	switch l {
+	case tracing.LevelTrace:
+		return LevelTrace
 	case tracing.LevelDebug:
 		return slog.LevelDebug
 	case tracing.LevelInfo:
 		return slog.LevelInfo
+	case tracing.LevelWarn:
+		return slog.LevelWarn
 	case tracing.LevelError:
 		return slog.LevelError
 	}
@@ -131,7 +225,8 @@ func translateTraceLevel(l tracing.TraceLevel) slog.Level {
 
 // ----------------------------------------------------------------------------
 
-// logentry is a helper for context tracing.
+// logentry is a helper for context tracing. logentries are never modified
+// after creation, i.e. P creates a new one.
 type logentry struct {
 	tracer *Tracer
 	attrs  []any
@@ -149,12 +244,43 @@ func (l *logentry) Errorf(s string, args ...any) {
 	l.tracer.output(tracing.LevelError, l.attrs, s, args...)
 }
 
+func (l *logentry) Warnf(s string, args ...any) {
+	l.tracer.output(tracing.LevelWarn, l.attrs, s, args...)
+}
+
+func (l *logentry) Tracef(s string, args ...any) {
+	l.tracer.output(tracing.LevelTrace, l.attrs, s, args...)
+}
+
+func (l *logentry) Error(msg string, kv ...any) {
+	l.tracer.event(tracing.LevelError, l.attrs, msg, kv)
+}
+
+func (l *logentry) Warn(msg string, kv ...any) {
+	l.tracer.event(tracing.LevelWarn, l.attrs, msg, kv)
+}
+
+func (l *logentry) Info(msg string, kv ...any) {
+	l.tracer.event(tracing.LevelInfo, l.attrs, msg, kv)
+}
+
+func (l *logentry) Debug(msg string, kv ...any) {
+	l.tracer.event(tracing.LevelDebug, l.attrs, msg, kv)
+}
+
 func (l *logentry) P(key string, val any) tracing.Trace {
-	l.attrs = append(l.attrs, key, val)
-	return l
+	val = tracing.Mask(key, val)
+	attrs := make([]any, len(l.attrs), len(l.attrs)+2)
+	copy(attrs, l.attrs)
+	return &logentry{
+		tracer: l.tracer,
+		attrs:  append(attrs, key, val),
+	}
 }
 
 func (l *logentry) SetTraceLevel(tracing.TraceLevel)  {}
 func (l *logentry) GetTraceLevel() tracing.TraceLevel { return l.tracer.GetTraceLevel() }
 func (l *logentry) SetOutput(io.Writer)               {}
 
+var _ tracing.StructuredTracer = &Tracer{}
+var _ tracing.StructuredTracer = &logentry{}
@@ -32,6 +32,7 @@ package gotestingadapter
 import (
 	"fmt"
 	"io"
+	"sync"
 	"testing"
 
 	"github.com/npillmayer/schuko/schukonf/testconfig"
@@ -42,20 +43,34 @@ import (
 // Tracer is our adapter implementation which implements interface
 // tracing.Trace, using a Go testing logger.
 type Tracer struct {
-	t     *testing.T
-	p     string
+	t     testing.TB
+	mx    sync.RWMutex // guards level
 	level tracing.TraceLevel
 }
 
-var logLevelPrefix = []string{"ERROR ", "INFO  ", "DEBUG "}
+// levelPrefix returns the message prefix for trace level l. Levels beyond
+// tracing.LevelTrace are clamped to it.
+func levelPrefix(l tracing.TraceLevel) string {
+	switch l {
+	case tracing.LevelError:
+		return "ERROR "
+	case tracing.LevelWarn:
+		return "WARN  "
+	case tracing.LevelInfo:
+		return "INFO  "
+	case tracing.LevelDebug:
+		return "DEBUG "
+	}
+	return "TRACE "
+}
 
 //var allTracers =
 
-// New creates a new Tracer instance valid for a testing.T.
-func New(t *testing.T) tracing.Trace {
+// New creates a new Tracer instance valid for a testing.T, or for any other
+// testing.TB, e.g. a benchmark.
+func New(t testing.TB) tracing.Trace {
 	return &Tracer{
 		t:     t,
-		p:     "",
 		level: tracing.LevelError,
 	}
 }
@@ -67,7 +82,6 @@ func GetAdapter(t *testing.T) tracing.Adapter {
 	return func() tracing.Trace {
 		return &Tracer{
 			t:     traceT,
-			p:     "",
 			level: tracing.LevelError,
 		}
 	}
@@ -107,71 +121,155 @@ func teardownTestingT() {
 
 // P is part of interface Trace
 func (tr *Tracer) P(key string, val any) tracing.Trace {
-	var p string
+	return &logentry{tracer: tr, p: field(key, val)}
+}
+
+// field formats a key/value pair as a prefix for trace messages.
+func field(key string, val any) string {
+	val = tracing.Mask(key, val)
 	switch v := val.(type) {
 	case rune:
-		p = fmt.Sprintf("[%s=%#U] ", key, v)
+		return fmt.Sprintf("[%s=%#U] ", key, v)
 	case int, int8, int16, int64, uint16, uint32, uint64:
-		p = fmt.Sprintf("[%s=%d] ", key, v)
+		return fmt.Sprintf("[%s=%d] ", key, v)
 	case string:
-		p = fmt.Sprintf("[%s=%s] ", key, v)
+		return fmt.Sprintf("[%s=%s] ", key, v)
 	default:
-		p = fmt.Sprintf("[%s=%v] ", key, v)
+		return fmt.Sprintf("[%s=%v] ", key, v)
 	}
-	tr.p = p
-	return tr
 }
 
-func (tr *Tracer) output(l tracing.TraceLevel, s string, args ...any) {
+func (tr *Tracer) output(l tracing.TraceLevel, p string, s string, args ...any) {
+	if tr.GetTraceLevel() < l {
+		return
+	}
+	prefix := levelPrefix(l) + p
 	if tr.t != nil {
-		prefix := fmt.Sprintf("%s%s", logLevelPrefix[int(l)], tr.p)
 		tr.t.Logf(prefix+s, args...)
-		tr.p = ""
 	} else if globalTestingT != nil {
-		prefix := fmt.Sprintf("%s%s", logLevelPrefix[int(l)], tr.p)
 		globalTestingT.Logf("depr."+prefix+s, args...)
-		tr.p = ""
 	}
 }
 
 // Debugf is part of interface Trace
 func (tr *Tracer) Debugf(s string, args ...any) {
-	if tr.level < tracing.LevelDebug {
-		return
-	}
-	tr.output(tracing.LevelDebug, s, args...)
+	tr.output(tracing.LevelDebug, "", s, args...)
 }
 
 // Infof is part of interface Trace
 func (tr *Tracer) Infof(s string, args ...any) {
-	if tr.level < tracing.LevelInfo {
-		return
-	}
-	tr.output(tracing.LevelInfo, s, args...)
+	tr.output(tracing.LevelInfo, "", s, args...)
+}
+
+// Warnf is part of interface LevelTracer
+func (tr *Tracer) Warnf(s string, args ...any) {
+	tr.output(tracing.LevelWarn, "", s, args...)
+}
+
+// Tracef is part of interface LevelTracer
+func (tr *Tracer) Tracef(s string, args ...any) {
+	tr.output(tracing.LevelTrace, "", s, args...)
 }
 
 // Errorf is part of interface Trace
 func (tr *Tracer) Errorf(s string, args ...any) {
-	if tr.level < tracing.LevelError {
-		return
-	}
-	tr.output(tracing.LevelError, s, args...)
+	tr.output(tracing.LevelError, "", s, args...)
+}
+
+// Error is part of interface StructuredTracer. This tracer appends attributes
+// to msg (see tracing.FormatAttrs).
+func (tr *Tracer) Error(msg string, kv ...any) {
+	tr.output(tracing.LevelError, "", "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+// Warn is part of interface StructuredTracer
+func (tr *Tracer) Warn(msg string, kv ...any) {
+	tr.output(tracing.LevelWarn, "", "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+// Info is part of interface StructuredTracer
+func (tr *Tracer) Info(msg string, kv ...any) {
+	tr.output(tracing.LevelInfo, "", "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+// Debug is part of interface StructuredTracer
+func (tr *Tracer) Debug(msg string, kv ...any) {
+	tr.output(tracing.LevelDebug, "", "%s", tracing.FormatAttrs(msg, kv...))
 }
 
 // SetTraceLevel is part of interface Trace
 func (tr *Tracer) SetTraceLevel(l tracing.TraceLevel) {
-	tr.p = ""
+	tr.mx.Lock()
+	defer tr.mx.Unlock()
 	tr.level = l
 }
 
 // GetTraceLevel is part of interface Trace
 func (tr *Tracer) GetTraceLevel() tracing.TraceLevel {
+	tr.mx.RLock()
+	defer tr.mx.RUnlock()
 	return tr.level
 }
 
 // SetOutput is part of interface Trace. This implementation ignores it.
 func (tr *Tracer) SetOutput(writer io.Writer) {}
 
+// ----------------------------------------------------------------------------
+
+// logentry is a helper for field tracing. Fields are accumulated as a prefix
+// for trace messages.
+type logentry struct {
+	tracer *Tracer
+	p      string // prefix
+}
+
+func (l *logentry) Debugf(s string, args ...any) {
+	l.tracer.output(tracing.LevelDebug, l.p, s, args...)
+}
+
+func (l *logentry) Infof(s string, args ...any) {
+	l.tracer.output(tracing.LevelInfo, l.p, s, args...)
+}
+
+func (l *logentry) Warnf(s string, args ...any) {
+	l.tracer.output(tracing.LevelWarn, l.p, s, args...)
+}
+
+func (l *logentry) Tracef(s string, args ...any) {
+	l.tracer.output(tracing.LevelTrace, l.p, s, args...)
+}
+
+func (l *logentry) Errorf(s string, args ...any) {
+	l.tracer.output(tracing.LevelError, l.p, s, args...)
+}
+
+func (l *logentry) Error(msg string, kv ...any) {
+	l.tracer.output(tracing.LevelError, l.p, "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+func (l *logentry) Warn(msg string, kv ...any) {
+	l.tracer.output(tracing.LevelWarn, l.p, "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+func (l *logentry) Info(msg string, kv ...any) {
+	l.tracer.output(tracing.LevelInfo, l.p, "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+func (l *logentry) Debug(msg string, kv ...any) {
+	l.tracer.output(tracing.LevelDebug, l.p, "%s", tracing.FormatAttrs(msg, kv...))
+}
+
+func (l *logentry) P(key string, val any) tracing.Trace {
+	return &logentry{tracer: l.tracer, p: l.p + field(key, val)}
+}
+
+func (l *logentry) SetTraceLevel(tracing.TraceLevel)  {}
+func (l *logentry) GetTraceLevel() tracing.TraceLevel { return l.tracer.GetTraceLevel() }
+func (l *logentry) SetOutput(io.Writer)               {}
+
+var _ tracing.StructuredTracer = &Tracer{}
+var _ tracing.StructuredTracer = &logentry{}
+
 // ----------------------------------------------------------------------
 
 // QuickConfig sets up a configuration suitable for test cases, including tracing.
@@ -1,6 +1,9 @@
 package logrusadapter_test
 
 import (
+	"bytes"
+	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/npillmayer/schuko/tracing"
@@ -16,3 +19,25 @@ func Test1(t *testing.T) {
 	l.SetTraceLevel(tracing.LevelError)
 	l.Debugf("Hello 3")
 }
+
+func TestStructuredEvents(t *testing.T) {
+	l := tracing.Structured(logrusadapter.New())
+	buf := &bytes.Buffer{}
+	l.SetOutput(buf)
+	l.SetTraceLevel(tracing.LevelInfo)
+	l.Debug("filtered", "key", "k0")
+	l.Info("cache miss", "key", "users/42", "size", 1024, "api_token", "abc")
+	tracing.Structured(l.P("req", 7)).Warn("slow", slog.Group("db", "ms", 250, "password", "pw"))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines of output, got %q", lines)
+	}
+	for i, want := range []string{
+		`msg="cache miss" api_token="******" key=users/42 size=1024`,
+		`level=warning msg=slow db.ms=250 db.password="******" req=7`,
+	} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("expected fields %s, got %q", want, lines[i])
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package logrusadapter_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/npillmayer/schuko/tracing"
+	"github.com/npillmayer/schuko/tracing/logrusadapter"
+)
+
+func TestWarnAndTraceLevels(t *testing.T) {
+	l := tracing.Leveled(logrusadapter.New())
+	buf := &bytes.Buffer{}
+	l.SetOutput(buf)
+	for _, level := range []tracing.TraceLevel{tracing.LevelError, tracing.LevelWarn,
+		tracing.LevelInfo, tracing.LevelDebug, tracing.LevelTrace} {
+		l.SetTraceLevel(level)
+		if lvl := l.GetTraceLevel(); lvl != level {
+			t.Errorf("expected level %s, got %s", level, lvl)
+		}
+	}
+	l.SetTraceLevel(tracing.LevelWarn)
+	l.Infof("info msg")
+	l.Warnf("warn msg")
+	l.SetTraceLevel(tracing.LevelTrace)
+	l.Tracef("trace msg")
+	out := buf.String()
+	if strings.Contains(out, "info msg") || !strings.Contains(out, "level=warning") ||
+		!strings.Contains(out, "level=trace") {
+		t.Errorf("expected warning and trace messages only, got %q", out)
+	}
+}
@@ -19,6 +19,7 @@ package logrusadapter
 
 import (
 	"io"
+	"log/slog"
 
 	"github.com/npillmayer/schuko/tracing"
 	"github.com/sirupsen/logrus"
@@ -28,12 +29,11 @@ import (
 // tracing.Trace, using a logrus logger.
 type Tracer struct {
 	log *logrus.Logger
-	p   *logrus.Entry
 }
 
 // New creates a new Tracer instance based on a logrus logger.
 func New() tracing.Trace {
-	return &Tracer{logrus.New(), nil}
+	return &Tracer{logrus.New()}
 }
 
 // NewAdapter creates an adapter (i.e., factory for tracing.Trace) to
@@ -44,38 +44,53 @@ func GetAdapter() tracing.Adapter {
 
 // Interface tracing.Trace
 func (t *Tracer) P(key string, val any) tracing.Trace {
-	t.p = t.log.WithField(key, val)
-	return t
+	val = tracing.Mask(key, val)
+	return &logentry{tracer: t, entry: t.log.WithField(key, val)}
 }
 
 // Interface tracing.Trace
 func (t *Tracer) Debugf(s string, args ...any) {
-	if t.p != nil {
-		t.p.Debugf(s, args...)
-		t.p = nil
-	} else {
-		t.log.Debugf(s, args...)
-	}
+	t.log.Debugf(s, args...)
 }
 
 // Interface tracing.Trace
 func (t *Tracer) Infof(s string, args ...any) {
-	if t.p != nil {
-		t.p.Infof(s, args...)
-		t.p = nil
-	} else {
-		t.log.Infof(s, args...)
-	}
+	t.log.Infof(s, args...)
+}
+
+// Interface tracing.LevelTracer
+func (t *Tracer) Warnf(s string, args ...any) {
+	t.log.Warnf(s, args...)
+}
+
+// Interface tracing.LevelTracer
+func (t *Tracer) Tracef(s string, args ...any) {
+	t.log.Tracef(s, args...)
 }
 
 // Interface tracing.Trace
 func (t *Tracer) Errorf(s string, args ...any) {
-	if t.p != nil {
-		t.p.Errorf(s, args...)
-		t.p = nil
-	} else {
-		t.log.Errorf(s, args...)
-	}
+	t.log.Errorf(s, args...)
+}
+
+// Interface tracing.StructuredTracer
+func (t *Tracer) Error(msg string, kv ...any) {
+	t.log.WithFields(fields(kv)).Error(msg)
+}
+
+// Interface tracing.StructuredTracer
+func (t *Tracer) Warn(msg string, kv ...any) {
+	t.log.WithFields(fields(kv)).Warn(msg)
+}
+
+// Interface tracing.StructuredTracer
+func (t *Tracer) Info(msg string, kv ...any) {
+	t.log.WithFields(fields(kv)).Info(msg)
+}
+
+// Interface tracing.StructuredTracer
+func (t *Tracer) Debug(msg string, kv ...any) {
+	t.log.WithFields(fields(kv)).Debug(msg)
 }
 
 // Interface tracing.Trace
@@ -90,17 +105,43 @@ func (t *Tracer) GetTraceLevel() tracing.TraceLevel {
 
 // Interface tracing.Trace
 func (t *Tracer) SetOutput(writer io.Writer) {
-	t.log.Out = writer
-	t.log.Formatter = &logrus.TextFormatter{}
+	t.log.SetOutput(writer)
+	t.log.SetFormatter(&logrus.TextFormatter{})
+}
+
+// fields converts attributes (see tracing.Attrs) to logrus fields. Attributes
+// of groups are flattened to dotted keys, e.g. "req.id".
+func fields(kv []any) logrus.Fields {
+	f := logrus.Fields{}
+	addFields(f, "", tracing.Attrs(kv...))
+	return f
+}
+
+func addFields(f logrus.Fields, prefix string, attrs []slog.Attr) {
+	for _, a := range attrs {
+		key, v := a.Key, a.Value.Resolve()
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if v.Kind() == slog.KindGroup {
+			addFields(f, key, v.Group())
+			continue
+		}
+		f[key] = v.Any()
+	}
 }
 
 func translateLogLevel(l logrus.Level) tracing.TraceLevel {
 	switch l {
+	case logrus.TraceLevel:
+		return tracing.LevelTrace
 	case logrus.DebugLevel:
 		return tracing.LevelDebug
 	case logrus.InfoLevel:
 		return tracing.LevelInfo
-	case logrus.ErrorLevel:
+	case logrus.WarnLevel:
+		return tracing.LevelWarn
+	case logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel:
 		return tracing.LevelError
 	}
 	return tracing.LevelDebug
@@ -108,12 +149,48 @@ func translateLogLevel(l logrus.Level) tracing.TraceLevel {
 
 func translateTraceLevel(l tracing.TraceLevel) logrus.Level {
 	switch l {
+	case tracing.LevelTrace:
+		return logrus.TraceLevel
 	case tracing.LevelDebug:
 		return logrus.DebugLevel
 	case tracing.LevelInfo:
 		return logrus.InfoLevel
+	case tracing.LevelWarn:
+		return logrus.WarnLevel
 	case tracing.LevelError:
 		return logrus.ErrorLevel
 	}
 	return logrus.DebugLevel
 }
+
+// ----------------------------------------------------------------------------
+
+// logentry is a helper for field tracing. It wraps a logrus entry, which
+// logrus never modifies after creation; WithField creates a new one.
+type logentry struct {
+	tracer *Tracer
+	entry  *logrus.Entry
+}
+
+func (l *logentry) Debugf(s string, args ...any) { l.entry.Debugf(s, args...) }
+func (l *logentry) Infof(s string, args ...any)  { l.entry.Infof(s, args...) }
+func (l *logentry) Warnf(s string, args ...any)  { l.entry.Warnf(s, args...) }
+func (l *logentry) Tracef(s string, args ...any) { l.entry.Tracef(s, args...) }
+func (l *logentry) Errorf(s string, args ...any) { l.entry.Errorf(s, args...) }
+
+func (l *logentry) Error(msg string, kv ...any) { l.entry.WithFields(fields(kv)).Error(msg) }
+func (l *logentry) Warn(msg string, kv ...any)  { l.entry.WithFields(fields(kv)).Warn(msg) }
+func (l *logentry) Info(msg string, kv ...any)  { l.entry.WithFields(fields(kv)).Info(msg) }
+func (l *logentry) Debug(msg string, kv ...any) { l.entry.WithFields(fields(kv)).Debug(msg) }
+
+func (l *logentry) P(key string, val any) tracing.Trace {
+	val = tracing.Mask(key, val)
+	return &logentry{tracer: l.tracer, entry: l.entry.WithField(key, val)}
+}
+
+func (l *logentry) SetTraceLevel(tracing.TraceLevel)  {}
+func (l *logentry) GetTraceLevel() tracing.TraceLevel { return l.tracer.GetTraceLevel() }
+func (l *logentry) SetOutput(io.Writer)               {}
+
+var _ tracing.StructuredTracer = &Tracer{}
+var _ tracing.StructuredTracer = &logentry{}
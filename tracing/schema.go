@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"sort"
+
+	"github.com/npillmayer/schuko"
+)
+
+// traceLevels lists all trace levels, from coarsest to finest.
+var traceLevels = []TraceLevel{LevelError, LevelWarn, LevelInfo, LevelDebug, LevelTrace}
+
+// RegisteredAdapters returns the keys of all tracing adapters registered with
+// RegisterTraceAdapter, in sorted order.
+func RegisteredAdapters() []string {
+	adapterMutex.RLock()
+	defer adapterMutex.RUnlock()
+	keys := make([]string, 0, len(knownTraceAdapters))
+	for key := range knownTraceAdapters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Schema returns a schema for the configuration keys consumed by tracing and
+// its sub-packages:
+//
+//	tracing.adapter       // one of the registered adapters
+//	tracing.destination   // "Stdout", "Stderr" or a file URI
+//	<levelKey>.*          // trace levels, e.g. "tracelevel.root: Debug"
+//
+// levelKey is the prefix key for trace levels, as passed to
+// trace2go.ConfigureRoot. If levelKey is empty, no rule for trace levels is
+// included. Applications will usually include this schema into their own:
+//
+//	schema := schuko.NewSchema(…).Include(tracing.Schema("tracelevel"))
+func Schema(levelKey string) *schuko.Schema {
+	levels := make([]string, len(traceLevels), len(traceLevels)+1)
+	for i, l := range traceLevels {
+		levels[i] = l.String()
+	}
+	levels = append(levels, "Warning") // alias accepted by TraceLevelFromString
+	s := schuko.NewSchema(
+		schuko.Setting("tracing.adapter", schuko.TypeString,
+			schuko.OneOfFunc(RegisteredAdapters),
+			schuko.Description("key of a registered tracing adapter")),
+		schuko.Setting("tracing.destination", schuko.TypeString,
+			schuko.Matches(`(?i)^(stdout|stderr|file:.+)$`),
+			schuko.Description("destination of tracing output: Stdout, Stderr or a file URI")),
+	)
+	if levelKey != "" {
+		s.Add(schuko.Setting(levelKey+".*", schuko.TypeString,
+			schuko.OneOf(levels...),
+			schuko.Description("trace level of a tracer")))
+	}
+	return s
+}
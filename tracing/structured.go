@@ -0,0 +1,144 @@
+package tracing
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/npillmayer/schuko"
+)
+
+// StructuredTracer is an optional extension of Trace for tracers supporting
+// structured events, i.e. messages with typed attributes:
+//
+//	tracing.Structured(tracer).Info("cache miss", "key", k, "size", n)
+//
+// Attributes are given as for log/slog: either as alternating keys and values,
+// or as slog.Attr values. Adapters for structured loggers map them onto native
+// attributes (slog) or fields (logrus), other adapters append them to the
+// message as “key=value” pairs. Values of secret keys are masked (see Mask).
+//
+// All tracers of the sub-packages of tracing implement StructuredTracer.
+// Clients should use Structured to get at these methods for any tracer.
+// There are no structured methods for LevelTrace, as wire-level tracing is
+// not meant to be processed by log collectors.
+type StructuredTracer interface {
+	LevelTracer
+	Error(msg string, kv ...any) // trace an event on error level
+	Warn(msg string, kv ...any)  // trace an event on level ≥ warn
+	Info(msg string, kv ...any)  // trace an event on level ≥ info
+	Debug(msg string, kv ...any) // trace an event on level ≥ debug
+}
+
+// Structured returns t as a StructuredTracer. If t does not implement
+// StructuredTracer, e.g. for third-party adapters, a wrapper is returned which
+// formats attributes into the message (see FormatAttrs).
+func Structured(t Trace) StructuredTracer {
+	if st, ok := t.(StructuredTracer); ok {
+		return st
+	}
+	return structuredFallback{Leveled(t)}
+}
+
+// structuredFallback adds structured methods to tracers not implementing
+// StructuredTracer.
+type structuredFallback struct {
+	LevelTracer
+}
+
+func (sf structuredFallback) Error(msg string, kv ...any) {
+	sf.Errorf("%s", FormatAttrs(msg, kv...))
+}
+
+func (sf structuredFallback) Warn(msg string, kv ...any) {
+	sf.Warnf("%s", FormatAttrs(msg, kv...))
+}
+
+func (sf structuredFallback) Info(msg string, kv ...any) {
+	sf.Infof("%s", FormatAttrs(msg, kv...))
+}
+
+func (sf structuredFallback) Debug(msg string, kv ...any) {
+	sf.Debugf("%s", FormatAttrs(msg, kv...))
+}
+
+// Attrs converts alternating keys and values, possibly mixed with slog.Attr
+// values, to a list of attributes, as log/slog does. A key without a value,
+// or a value where a key is expected, results in an attribute with key
+// "!BADKEY". Values of secret keys are masked, as with Mask. Keys of
+// attributes within groups are checked as dotted keys, e.g.
+// slog.Group("db", "password", pw) masks pw as the value of "db.password".
+func Attrs(kv ...any) []slog.Attr {
+	var attrs []slog.Attr
+	for len(kv) > 0 {
+		var a slog.Attr
+		switch x := kv[0].(type) {
+		case slog.Attr:
+			a, kv = x, kv[1:]
+		case string:
+			if len(kv) == 1 {
+				a, kv = slog.String("!BADKEY", x), nil
+			} else {
+				a, kv = slog.Any(x, kv[1]), kv[2:]
+			}
+		default:
+			a, kv = slog.Any("!BADKEY", x), kv[1:]
+		}
+		attrs = append(attrs, maskAttr("", a))
+	}
+	return attrs
+}
+
+// maskAttr masks the value of a if its key, prefixed by the keys of enclosing
+// groups, is secret. Groups are masked recursively.
+func maskAttr(prefix string, a slog.Attr) slog.Attr {
+	key := a.Key
+	if prefix != "" {
+		key = prefix + "." + key
+	}
+	if schuko.IsSecretKey(key) {
+		a.Value = slog.StringValue(schuko.Redacted)
+		return a
+	}
+	if v := a.Value.Resolve(); v.Kind() == slog.KindGroup {
+		group := v.Group()
+		masked := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			masked[i] = maskAttr(key, ga)
+		}
+		a.Value = slog.GroupValue(masked...)
+	}
+	return a
+}
+
+// FormatAttrs formats a message followed by its attributes (see Attrs) as
+// “key=value” pairs, quoting values if necessary, e.g.
+//
+//	cache miss key=users/42 size=1024 reason="not found"
+//
+// Attributes of groups are formatted with dotted keys, e.g. “req.id=7”.
+func FormatAttrs(msg string, kv ...any) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	formatAttrs(&b, "", Attrs(kv...))
+	return b.String()
+}
+
+func formatAttrs(b *strings.Builder, prefix string, attrs []slog.Attr) {
+	for _, a := range attrs {
+		v := a.Value.Resolve()
+		key := a.Key
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if v.Kind() == slog.KindGroup {
+			formatAttrs(b, key, v.Group())
+			continue
+		}
+		s := v.String()
+		if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+			s = fmt.Sprintf("%q", s)
+		}
+		fmt.Fprintf(b, " %s=%s", key, s)
+	}
+}
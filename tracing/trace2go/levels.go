@@ -0,0 +1,105 @@
+package trace2go
+
+import (
+	"strings"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/tracing"
+)
+
+// LevelResolution describes how the trace level of a tracer has been determined.
+type LevelResolution struct {
+	Name  string             // name of the tracer
+	Level tracing.TraceLevel // effective trace level
+	Key   string             // configuration key supplying the level, empty for the default
+}
+
+// ResolveLevel returns the effective trace level for a tracer name, as
+// configured by the configuration held by the root tracer, together with the
+// configuration key which supplied it.
+//
+// Trace levels are inherited along dotted tracer names, in the spirit of log4j.
+// For a tracer named "a.b.c" and a prefix key "tracelevel", keys will be searched
+// in this order:
+//
+//	tracelevel.a.b.c
+//	tracelevel.a.b.c.root
+//	tracelevel.a.b.*   // wildcards apply to descendants only
+//	tracelevel.a.b
+//	tracelevel.a.b.root
+//	tracelevel.a.*
+//	tracelevel.a
+//	tracelevel.a.root
+//	tracelevel.root
+//
+// If none of these keys is set, the level defaults to LevelInfo.
+//
+// Flat configurations, e.g. testconfig.Conf or environment variables, may set
+// both "tracelevel.myapp" and "tracelevel.myapp.db". Hierarchical formats, e.g.
+// NestedText or YAML files read by koanfadapter, cannot express this, as a key
+// may either hold a value or nested keys (schuko.Unflatten drops such values as
+// well). There, the level of a tracer with configured descendants goes below
+// key "root" of its section:
+//
+//	tracelevel:
+//	    root: Error
+//	    myapp:
+//	        root: Debug    # level of myapp and its descendants
+//	        db: Info       # level of myapp.db and its descendants
+//
+// If the root tracer has not been configured, ResolveLevel returns the
+// level of the root tracer.
+func ResolveLevel(name string) LevelResolution {
+	r, ok := Root().(*rootTracer)
+	if !ok {
+		return LevelResolution{Name: name, Level: Root().GetTraceLevel()}
+	}
+	mx.RLock()
+	config := r.config
+	mx.RUnlock()
+	return resolveLevel(config, r.prefixKey, name)
+}
+
+func resolveLevel(conf schuko.Configuration, prefixKey string, name string) LevelResolution {
+	res := LevelResolution{Name: name, Level: tracing.LevelInfo}
+	if v, key := lookupLevel(conf, prefixKey, name); v != "" {
+		res.Level, res.Key = tracing.TraceLevelFromString(v), key
+		return res
+	}
+	for ancestor := name; ; {
+		i := strings.LastIndexByte(ancestor, '.')
+		if i < 0 {
+			break
+		}
+		ancestor = ancestor[:i]
+		if v, key := lookupValue(conf, prefixKey, ancestor+".*"); v != "" {
+			res.Level, res.Key = tracing.TraceLevelFromString(v), key
+			return res
+		}
+		if v, key := lookupLevel(conf, prefixKey, ancestor); v != "" {
+			res.Level, res.Key = tracing.TraceLevelFromString(v), key
+			return res
+		}
+	}
+	if name != "root" {
+		if v, key := lookupValue(conf, prefixKey, "root"); v != "" {
+			res.Level, res.Key = tracing.TraceLevelFromString(v), key
+		}
+	}
+	return res
+}
+
+// lookupLevel looks up the level for a tracer name, either set directly or,
+// for hierarchical configurations, below key "root" of the tracer's section.
+// Adapters may render a section as a string, e.g. "map[db:Info root:Debug]",
+// therefore values of sections are not taken as levels.
+func lookupLevel(conf schuko.Configuration, prefixKey string, name string) (string, string) {
+	v, key := lookupValue(conf, prefixKey, name)
+	if v != "" && schuko.Typed(conf).GetStringMap(key) == nil {
+		return v, key
+	}
+	if name == "root" {
+		return "", ""
+	}
+	return lookupValue(conf, prefixKey, name+".root")
+}
@@ -0,0 +1,160 @@
+package trace2go
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/tracing"
+	"github.com/npillmayer/schuko/tracing/appender"
+)
+
+// Reconfigure re-applies configuration to the root tracer and to all tracers
+// created by trace2go. If conf is nil, the configuration passed to ConfigureRoot
+// is consulted again; this is useful for configurations which are able to
+// reload their values.
+//
+// Re-configuration consists of the following steps:
+//
+// a) set the trace level of every tracer from the trace level keys,
+//
+// b) re-direct output of every tracer, if the tracing destination changed,
+//
+// c) exchange the adapter of every tracer, if the adapter key changed.
+//
+// Tracers are re-configured in place: tracer instances held by clients stay valid
+// and will reflect the new configuration. If the new tracing destination cannot
+// be opened, tracers keep their previous destination, the other steps are
+// performed nevertheless, and the error is returned. A destination replaced
+// is closed, unless it is os.Stdout or os.Stderr.
+func Reconfigure(conf schuko.Configuration) error {
+	r, ok := Root().(*rootTracer)
+	if !ok {
+		return errors.New("root tracer has not been configured")
+	}
+	// we must not hold mx while calling into package tracing or appender, as
+	// these may trace to the root tracer
+	mx.Lock()
+	if conf != nil {
+		r.config = conf
+	}
+	config := r.config
+	name := tracing.AdapterNameFromConfiguration(config)
+	dest := config.GetString("tracing.destination")
+	newAdapter := name != r.adapterName
+	newDest := newAdapter || dest != r.destination
+	mx.Unlock()
+	var adapter tracing.Adapter
+	if newAdapter {
+		adapter = tracing.GetAdapterFromConfiguration(config, r.optAdapterKey)
+	}
+	var out io.Writer
+	var err error
+	if newDest {
+		if out, err = appender.AppenderFromConfig(config); err != nil {
+			out = nil // keep the previous destination
+		}
+	}
+	mx.Lock()
+	if newAdapter {
+		r.adapter, r.adapterName = adapter, name
+	}
+	var prevOut io.Writer
+	if out != nil {
+		prevOut = r.out
+		r.destination, r.out = dest, out
+	} else if newAdapter {
+		out = r.out // new tracers keep writing to the previous destination
+	}
+	adapter = r.adapter
+	mx.Unlock()
+	rootSlot := r.Trace.(*tracerSlot)
+	childMx.Lock()
+	defer childMx.Unlock()
+	slots := map[string]*tracerSlot{"root": rootSlot}
+	for name, slot := range selectableTracers {
+		if name != "root" {
+			slots[name] = slot
+		}
+	}
+	for name, slot := range slots {
+		if newAdapter {
+			t := adapter()
+			t.SetTraceLevel(slot.GetTraceLevel())
+			slot.set(t)
+		}
+		if out != nil {
+			slot.SetOutput(out)
+		}
+		if name == "root" {
+			if level := getValue(config, r.prefixKey, name); level != "" {
+				slot.SetTraceLevel(tracing.TraceLevelFromString(level))
+			} // otherwise root tracer keeps its level
+			continue
+		}
+		slot.SetTraceLevel(resolveLevel(config, r.prefixKey, name).Level)
+	}
+	if prevOut != nil && prevOut != out {
+		closeOutput(prevOut)
+	}
+	return err
+}
+
+// closeOutput closes a writer opened for a tracing destination. Standard
+// streams are left open.
+func closeOutput(w io.Writer) {
+	if c, ok := w.(io.Closer); ok && w != os.Stdout && w != os.Stderr {
+		c.Close()
+	}
+}
+
+// ConfigurationChanged is a callback for configurations which notify subscribers
+// about changes of their values. It calls Reconfigure(nil) and reports
+// errors to the root tracer. Example for a koanfadapter.KConf:
+//
+//	conf.Subscribe("", trace2go.ConfigurationChanged)
+func ConfigurationChanged(changed []string) {
+	if err := Reconfigure(nil); err != nil {
+		Root().Errorf("re-configuring tracers failed: %v", err)
+	}
+}
+
+// --- Tracer slots ----------------------------------------------------------
+
+// tracerSlot is a tracing.Trace which delegates to an exchangeable tracer.
+// trace2go hands out slots to clients, thus being able to exchange the
+// underlying tracer during re-configuration.
+type tracerSlot struct {
+	mx sync.RWMutex
+	t  tracing.Trace
+}
+
+func (s *tracerSlot) get() tracing.Trace {
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+	return s.t
+}
+
+func (s *tracerSlot) set(t tracing.Trace) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.t = t
+}
+
+func (s *tracerSlot) Errorf(msg string, args ...any)      { s.get().Errorf(msg, args...) }
+func (s *tracerSlot) Infof(msg string, args ...any)       { s.get().Infof(msg, args...) }
+func (s *tracerSlot) Debugf(msg string, args ...any)      { s.get().Debugf(msg, args...) }
+func (s *tracerSlot) Warnf(msg string, args ...any)       { tracing.Leveled(s.get()).Warnf(msg, args...) }
+func (s *tracerSlot) Tracef(msg string, args ...any)      { tracing.Leveled(s.get()).Tracef(msg, args...) }
+func (s *tracerSlot) Error(msg string, kv ...any)         { tracing.Structured(s.get()).Error(msg, kv...) }
+func (s *tracerSlot) Warn(msg string, kv ...any)          { tracing.Structured(s.get()).Warn(msg, kv...) }
+func (s *tracerSlot) Info(msg string, kv ...any)          { tracing.Structured(s.get()).Info(msg, kv...) }
+func (s *tracerSlot) Debug(msg string, kv ...any)         { tracing.Structured(s.get()).Debug(msg, kv...) }
+func (s *tracerSlot) P(key string, val any) tracing.Trace { return s.get().P(key, val) }
+func (s *tracerSlot) SetTraceLevel(l tracing.TraceLevel)  { s.get().SetTraceLevel(l) }
+func (s *tracerSlot) GetTraceLevel() tracing.TraceLevel   { return s.get().GetTraceLevel() }
+func (s *tracerSlot) SetOutput(w io.Writer)               { s.get().SetOutput(w) }
+
+var _ tracing.StructuredTracer = &tracerSlot{}
@@ -136,11 +136,14 @@ func AdapterKey(key string) RootOption {
 // --- Root tracer type ------------------------------------------------------
 
 type rootTracer struct {
-	tracing.Trace
-	config          schuko.Configuration
+	tracing.Trace                        // a *tracerSlot
+	config          schuko.Configuration // guarded by mx after initialization
 	prefixKey       string
 	optAdapterKey   string
-	adapter         tracing.Adapter
+	adapter         tracing.Adapter // guarded by mx after initialization
+	adapterName     string          // key of adapter, guarded by mx after initialization
+	destination     string          // tracing destination, guarded by mx after initialization
+	out             io.Writer       // writer opened for destination, guarded by mx after initialization
 	replaceChildren bool
 }
 
@@ -169,11 +172,14 @@ func (t *rootTracer) init() {
 		}
 	}
 	t.adapter = adapter // remember it for child traces
-	t.Trace = adapter()
+	t.adapterName = tracing.AdapterNameFromConfiguration(t.config)
+	t.destination = t.config.GetString("tracing.destination")
+	t.Trace = &tracerSlot{t: adapter()}
 	if l := getValue(t.config, t.prefixKey, "root"); l != "" {
 		t.SetTraceLevel(tracing.TraceLevelFromString(l))
 	}
 	if w, err := appender.AppenderFromConfig(t.config); err == nil {
+		t.out = w
 		t.SetOutput(w)
 	}
 }
@@ -203,7 +209,10 @@ var _ tracing.TraceSelector = selector(trace2goSelector)
 
 // We will manage a map of keys -> tracers.
 var childMx *sync.RWMutex = &sync.RWMutex{}
-var selectableTracers map[string]tracing.Trace = make(map[string]tracing.Trace, 10)
+
+// Tracers are handed out to clients as *tracerSlot, which enables re-configuration
+// without replacing tracer instances held by clients.
+var selectableTracers map[string]*tracerSlot = make(map[string]*tracerSlot, 10)
 
 // GetTracer returns the tracer associated with name, if any.
 func GetTracer(name string) tracing.Trace {
@@ -232,17 +241,20 @@ func GetOrCreateTracer(name string) tracing.Trace {
 //
 // The tracer will be configured using the schuko.Configuration held by the
 // root tracer. This may set a trace level and/or an output destination.
+// Trace levels are inherited along dotted names (see ResolveLevel).
 //
 // If parameter `replace` is true, a new tracer will replace an existing one
 // for this name.
 func NewTracer(name string, replace bool) (tracing.Trace, tracing.Trace) {
-	var trace tracing.Trace
+	var trace *tracerSlot
 	if r, ok := Root().(*rootTracer); ok {
-		trace = r.adapter()
-		level := getValue(r.config, r.prefixKey, name)
-		trace.SetTraceLevel(tracing.TraceLevelFromString(level))
-		if w, err := appender.AppenderFromConfig(r.config); err == nil {
-			trace.SetOutput(w)
+		mx.RLock()
+		adapter, config, out := r.adapter, r.config, r.out
+		mx.RUnlock()
+		trace = &tracerSlot{t: adapter()}
+		trace.SetTraceLevel(resolveLevel(config, r.prefixKey, name).Level)
+		if out != nil { // share the destination opened by the root tracer
+			trace.SetOutput(out)
 		}
 	} else {
 		return Root(), nil
@@ -263,27 +275,34 @@ func NewTracer(name string, replace bool) (tracing.Trace, tracing.Trace) {
 // setTracer associates a tracer with a name. Returns the tracer previously
 // occupying the slot, if any.
 //
-// New tracers replacing existing ones will inherit their trace level.
+// New tracers replacing existing ones will inherit their trace level. Clients
+// holding the tracer for name will use the new tracer from now on.
 //
 // Not protected by childMx.
 func setTracer(name string, trace tracing.Trace) tracing.Trace {
-	prev, ok := selectableTracers[name]
-	selectableTracers[name] = trace
+	slot, ok := selectableTracers[name]
 	if !ok {
-		return prev
+		selectableTracers[name] = &tracerSlot{t: trace}
+		return nil
 	}
+	prev := slot.get()
 	trace.SetTraceLevel(prev.GetTraceLevel())
+	slot.set(trace)
 	return prev
 }
 
 // Teardown removes the trace2go root tracer and any existing child tracers,
 // and detaches trace2go from the tracing-facade (`tracing.Select(…)`).
+// The tracing destination is closed, unless it is os.Stdout or os.Stderr.
 func Teardown() {
 	mx.Lock()
 	defer mx.Unlock()
 	childMx.Lock()
 	defer childMx.Unlock()
-	selectableTracers = make(map[string]tracing.Trace)
+	if r, ok := root.(*rootTracer); ok && r.out != nil {
+		closeOutput(r.out)
+	}
+	selectableTracers = make(map[string]*tracerSlot)
 	root = nil
 	tracing.SetTraceSelector(nil)
 }
@@ -311,6 +330,12 @@ func (bbt _BareBonesTrace) Debugf(string, ...any) {}
 // Infof does nothing
 func (bbt _BareBonesTrace) Infof(string, ...any) {}
 
+// Warnf does nothing
+func (bbt _BareBonesTrace) Warnf(string, ...any) {}
+
+// Tracef does nothing
+func (bbt _BareBonesTrace) Tracef(string, ...any) {}
+
 // Errorf traces errors. It is the only trace level implemented for
 // bare bones tracers.
 func (bbt _BareBonesTrace) Errorf(msg string, args ...any) {
@@ -339,17 +364,24 @@ func (bbt *_BareBonesTrace) SetOutput(w io.Writer) {
 // ---------------------------------------------------------------------------
 
 func getValue(conf schuko.Configuration, prefixKey string, key string) string {
+	v, _ := lookupValue(conf, prefixKey, key)
+	return v
+}
+
+// lookupValue is like getValue, but additionally returns the configuration key
+// which supplied the value.
+func lookupValue(conf schuko.Configuration, prefixKey string, key string) (string, string) {
 	k := prefixKey + key
 	if v := conf.GetString(k); v != "" {
-		return v
+		return v, k
 	}
 	k = prefixKey + "." + key
 	if v := conf.GetString(k); v != "" {
-		return v
+		return v, k
 	}
 	k = prefixKey + "/" + key
 	if v := conf.GetString(k); v != "" {
-		return v
+		return v, k
 	}
-	return ""
+	return "", ""
 }
@@ -4,8 +4,13 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/rawbytes"
+	"github.com/npillmayer/schuko/schukonf/koanfadapter"
 	"github.com/npillmayer/schuko/schukonf/testconfig"
 	"github.com/npillmayer/schuko/tracing"
 	"github.com/npillmayer/schuko/tracing/trace2go"
@@ -92,8 +97,157 @@ func TestSelection(t *testing.T) {
 	}
 }
 
+func TestReconfigure(t *testing.T) {
+	tracing.RegisterTraceAdapter("test", getTT, true)
+	tracing.RegisterTraceAdapter("other", getOther, true)
+	tracing.SetTraceSelector(trace2go.Selector())
+	defer trace2go.Teardown()
+	conf := testconfig.Conf{
+		"tracing.adapter": "test",
+		"LEVEL.root":      "Info",
+		"LEVEL.db":        "Error",
+	}
+	trace2go.ConfigureRoot(conf, "LEVEL")
+	tracer := tracing.Select("db") // held by library code
+	if l := tracer.GetTraceLevel(); l != tracing.LevelError {
+		t.Fatalf("expected tracer db at level Error, is %s", l)
+	}
+	conf["LEVEL.db"] = "Debug"
+	if err := trace2go.Reconfigure(nil); err != nil {
+		t.Fatal(err)
+	}
+	if l := tracer.GetTraceLevel(); l != tracing.LevelDebug {
+		t.Errorf("expected tracer db to be raised to level Debug, is %s", l)
+	}
+	conf["tracing.adapter"] = "other"
+	if err := trace2go.Reconfigure(conf); err != nil {
+		t.Fatal(err)
+	}
+	buf := &bytes.Buffer{}
+	tracer.SetOutput(buf)
+	tracer.Infof("hello")
+	if out := buf.String(); out != "other:hello" {
+		t.Errorf("expected tracer db to use the new adapter, got %q", out)
+	}
+	if tracing.Select("db") != tracer {
+		t.Errorf("expected tracer instance to be unchanged")
+	}
+}
+
+func TestReconfigureFailingDestination(t *testing.T) {
+	tracing.RegisterTraceAdapter("test", getTT, true)
+	tracing.RegisterTraceAdapter("other", getOther, true)
+	defer trace2go.Teardown()
+	conf := testconfig.Conf{
+		"tracing.adapter": "test",
+		"LEVEL.db":        "Error",
+	}
+	trace2go.ConfigureRoot(conf, "LEVEL")
+	tracer, _ := trace2go.NewTracer("db", false)
+	conf["tracing.adapter"] = "other"
+	blocker := filepath.Join(t.TempDir(), "blocker") // a file, thus not a directory for the log
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	conf["tracing.destination"] = "file://" + filepath.Join(blocker, "trace.log")
+	conf["LEVEL.db"] = "Debug"
+	if err := trace2go.Reconfigure(nil); err == nil {
+		t.Errorf("expected failing destination to be reported")
+	}
+	if l := tracer.GetTraceLevel(); l != tracing.LevelDebug {
+		t.Errorf("expected tracer db to be raised to level Debug, is %s", l)
+	}
+	buf := &bytes.Buffer{}
+	tracer.SetOutput(buf)
+	tracer.Infof("hello")
+	if out := buf.String(); out != "other:hello" {
+		t.Errorf("expected tracer db to use the new adapter, got %q", out)
+	}
+}
+
+func TestReconfigureClosesDestination(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("counting open files requires /proc")
+	}
+	tracing.RegisterTraceAdapter("test", getTT, true)
+	defer trace2go.Teardown()
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.log")
+	conf := testconfig.Conf{
+		"tracing.adapter":     "test",
+		"tracing.destination": "file://" + first,
+	}
+	trace2go.ConfigureRoot(conf, "LEVEL")
+	trace2go.NewTracer("db", false)
+	if n := openFiles(t, first); n != 1 {
+		t.Fatalf("expected destination to be opened once, is open %d times", n)
+	}
+	conf["tracing.destination"] = "file://" + filepath.Join(dir, "second.log")
+	if err := trace2go.Reconfigure(nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := openFiles(t, first); n != 0 {
+		t.Errorf("expected previous destination to be closed, is open %d times", n)
+	}
+}
+
+// openFiles counts the file descriptors of the process referring to path.
+func openFiles(t *testing.T, path string) int {
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, fd := range fds {
+		if target, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name())); err == nil && target == path {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLevelInheritance(t *testing.T) {
+	tracing.RegisterTraceAdapter("test", getTT, true)
+	defer trace2go.Teardown()
+	conf := testconfig.Conf{
+		"tracing.adapter":     "test",
+		"tracelevel.root":     "Error",
+		"tracelevel.myapp":    "Debug",
+		"tracelevel.lib.*":    "Debug",
+		"tracelevel.myapp.db": "Info",
+	}
+	trace2go.ConfigureRoot(conf, "tracelevel")
+	for _, x := range []struct {
+		name  string
+		level tracing.TraceLevel
+		key   string
+	}{
+		{"myapp", tracing.LevelDebug, "tracelevel.myapp"},
+		{"myapp.web.handler", tracing.LevelDebug, "tracelevel.myapp"},
+		{"myapp.db.pool", tracing.LevelInfo, "tracelevel.myapp.db"},
+		{"lib", tracing.LevelError, "tracelevel.root"},
+		{"lib.x", tracing.LevelDebug, "tracelevel.lib.*"},
+		{"other", tracing.LevelError, "tracelevel.root"},
+	} {
+		res := trace2go.ResolveLevel(x.name)
+		if res.Level != x.level || res.Key != x.key {
+			t.Errorf("expected %s to resolve to %s by %q, got %s by %q", x.name, x.level, x.key, res.Level, res.Key)
+		}
+		tracer, _ := trace2go.NewTracer(x.name, true)
+		if l := tracer.GetTraceLevel(); l != x.level {
+			t.Errorf("expected tracer %s at level %s, is %s", x.name, x.level, l)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 
+func getOther() tracing.Trace {
+	tt := newTestTracer()
+	tt.prefix = "other:"
+	return tt
+}
+
 func getTT() tracing.Trace {
 	return newTestTracer()
 }
@@ -108,13 +262,57 @@ func newTestTracer() *testTracer {
 
 type testTracer struct {
 	tracing.Trace
-	out io.Writer
+	out    io.Writer
+	prefix string
+	level  tracing.TraceLevel
 }
 
 func (tt *testTracer) Infof(msg string, args ...any) {
-	tt.out.Write([]byte(msg)) // for test: ignore args
+	tt.out.Write([]byte(tt.prefix + msg)) // for test: ignore args
+}
+
+func (tt *testTracer) SetTraceLevel(l tracing.TraceLevel) {
+	tt.level = l
+}
+
+func (tt *testTracer) GetTraceLevel() tracing.TraceLevel {
+	return tt.level
 }
 
 func (tt *testTracer) SetOutput(w io.Writer) {
 	tt.out = w
 }
+
+func TestLevelInheritanceHierarchical(t *testing.T) {
+	tracing.RegisterTraceAdapter("test", getTT, true)
+	defer trace2go.Teardown()
+	nt := `tracing:
+    adapter: test
+tracelevel:
+    root: Error
+    myapp:
+        root: Debug
+        db: Info
+`
+	parser, _ := koanfadapter.ParserFor(".nt")
+	k := koanf.New(".")
+	if err := k.Load(rawbytes.Provider([]byte(nt)), parser); err != nil {
+		t.Fatal(err)
+	}
+	trace2go.ConfigureRoot(koanfadapter.New(k, "", nil), "tracelevel")
+	for _, x := range []struct {
+		name  string
+		level tracing.TraceLevel
+		key   string
+	}{
+		{"myapp", tracing.LevelDebug, "tracelevel.myapp.root"},
+		{"myapp.web", tracing.LevelDebug, "tracelevel.myapp.root"},
+		{"myapp.db.pool", tracing.LevelInfo, "tracelevel.myapp.db"},
+		{"other", tracing.LevelError, "tracelevel.root"},
+	} {
+		res := trace2go.ResolveLevel(x.name)
+		if res.Level != x.level || res.Key != x.key {
+			t.Errorf("expected %s to resolve to %s by %q, got %s by %q", x.name, x.level, x.key, res.Level, res.Key)
+		}
+	}
+}
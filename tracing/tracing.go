@@ -19,6 +19,17 @@ implementation and creates no dependencies. Deciding for a concrete logger/trace
 is completely up to the main application, where it's perfectly okay to create
 a logger/tracer-dependency.
 
+# Trace Levels
+
+Tracers support five levels, from LevelError to LevelTrace. Earlier versions
+knew levels LevelError, LevelInfo and LevelDebug only, numbered 0, 1 and 2.
+With the introduction of LevelWarn, LevelInfo and LevelDebug have changed
+their numeric values to 2 and 3. Third-party adapters must not rely on the
+numeric values of levels, e.g. by using them as indices into tables of three
+entries; they should switch on the level constants instead, treating unknown
+levels as the nearest level supported. Adapters not implementing LevelTracer
+will be called with levels LevelWarn and LevelTrace as well (see Leveled).
+
 # Resources
 
 https://dave.cheney.net/2015/11/05/lets-talk-about-logging
@@ -48,19 +59,26 @@ import (
 // All concrete Tracer implementations will support trace-levels.
 type TraceLevel uint8
 
-// We support three trace levels.
+// We support five trace levels, from coarsest to finest. Levels are ordered,
+// i.e. a tracer set to a level will trace messages of all coarser levels.
 const (
 	LevelError TraceLevel = iota
+	LevelWarn
 	LevelInfo
 	LevelDebug
+	LevelTrace // wire-level tracing, finer than LevelDebug
 )
 
 func (tl TraceLevel) String() string {
 	switch tl {
+	case LevelTrace:
+		return "Trace"
 	case LevelDebug:
 		return "Debug"
 	case LevelInfo:
 		return "Info"
+	case LevelWarn:
+		return "Warn"
 	case LevelError:
 		return "Error"
 	}
@@ -68,16 +86,20 @@ func (tl TraceLevel) String() string {
 }
 
 // TraceLevelFromString will find a trace level from a string.
-// It will recognize "Debug", "Info" and "Error". Default is
-// LevelInfo, if `sl` is not recognized.
+// It will recognize "Trace", "Debug", "Info", "Warn" (or "Warning") and "Error".
+// Default is LevelInfo, if `sl` is not recognized.
 //
 // String comparison is case-insensitive.
 func TraceLevelFromString(sl string) TraceLevel {
 	switch strings.ToLower(sl) {
+	case "trace":
+		return LevelTrace
 	case "debug":
 		return LevelDebug
 	case "info":
 		return LevelInfo
+	case "warn", "warning":
+		return LevelWarn
 	case "error":
 		return LevelError
 	}
@@ -92,6 +114,14 @@ func TraceLevelFromString(sl string) TraceLevel {
 //
 //	tracer.P("mycontext", "value").Debugf("message within context")
 //
+// P must not modify the tracer it is called on. Instead it returns a derived
+// tracer carrying the field, which is immutable as well. Derived tracers may
+// be chained, kept for repeated use and passed across goroutines:
+//
+//	req := tracer.P("request", id)
+//	go func() { req.P("step", 1).Debugf("started") }()
+//	req.Infof("done")  // carries field "request" only
+//
 // Tracers should be prepared to trace to console as well as to a file.
 // By convention, no newlines at the end of tracing messages will be passed
 // by clients.
@@ -105,6 +135,53 @@ type Trace interface {
 	SetOutput(io.Writer)       // route tracing output to a writer
 }
 
+// LevelTracer is an optional extension of Trace for tracers supporting levels
+// LevelWarn and LevelTrace natively. All tracers of the sub-packages of tracing
+// implement it. Clients should use Leveled to get at these methods for any
+// tracer.
+type LevelTracer interface {
+	Trace
+	Warnf(string, ...any)  // trace on level ≥ warn
+	Tracef(string, ...any) // trace on level ≥ trace
+}
+
+// Leveled returns t as a LevelTracer. If t does not implement LevelTracer,
+// e.g. for third-party adapters, a wrapper is returned which falls back to
+// Infof for warnings and to Debugf for wire-level tracing. The fallbacks trace
+// only if t reports a trace level of at least LevelWarn, or LevelTrace,
+// respectively. At LevelWarn, t would suppress calls to Infof, therefore
+// warnings fall back to Errorf.
+//
+// Usage:
+//
+//	tracing.Leveled(tracer).Warnf("disk %s almost full", disk)
+func Leveled(t Trace) LevelTracer {
+	if lt, ok := t.(LevelTracer); ok {
+		return lt
+	}
+	return fallbackTracer{t}
+}
+
+// fallbackTracer adds level methods to tracers not implementing LevelTracer.
+type fallbackTracer struct {
+	Trace
+}
+
+func (ft fallbackTracer) Warnf(msg string, args ...any) {
+	switch level := ft.GetTraceLevel(); {
+	case level >= LevelInfo:
+		ft.Infof(msg, args...)
+	case level == LevelWarn:
+		ft.Errorf(msg, args...)
+	}
+}
+
+func (ft fallbackTracer) Tracef(msg string, args ...any) {
+	if ft.GetTraceLevel() >= LevelTrace {
+		ft.Debugf(msg, args...)
+	}
+}
+
 // Tracefile is the global file where tracing goes to.
 // If tracing goes to a file (globally), variable Tracefile should
 // point to it. It need not be set if tracing goes to console.
@@ -206,10 +283,7 @@ func RegisterTraceAdapter(key string, adapter Adapter, replace bool) {
 // If the key is not registered, Adapter
 // defaults to a no-op tracer.
 func GetAdapterFromConfiguration(conf schuko.Configuration, optKey string) Adapter {
-	adapterPackage := conf.GetString("tracing.adapter")
-	if adapterPackage == "" {
-		adapterPackage = conf.GetString("tracing")
-	}
+	adapterPackage := AdapterNameFromConfiguration(conf)
 	adapterMutex.RLock()
 	defer adapterMutex.RUnlock()
 	adapter, ok := knownTraceAdapters[adapterPackage]
@@ -220,6 +294,18 @@ func GetAdapterFromConfiguration(conf schuko.Configuration, optKey string) Adapt
 	return adapter
 }
 
+// AdapterNameFromConfiguration returns the key of the tracing adapter selected
+// by configuration conf, i.e. the value of "tracing.adapter" or, if that is
+// empty, of "tracing". This is the key GetAdapterFromConfiguration looks up.
+// If no adapter is configured, the empty string is returned.
+func AdapterNameFromConfiguration(conf schuko.Configuration) string {
+	adapterPackage := conf.GetString("tracing.adapter")
+	if adapterPackage == "" {
+		adapterPackage = conf.GetString("tracing")
+	}
+	return adapterPackage
+}
+
 // --- Dumping values to trace -----------------------------------------------
 
 // With prepares to dump a data structure to a Trace.
@@ -232,6 +318,11 @@ func GetAdapterFromConfiguration(conf schuko.Configuration, optKey string) Adapt
 // Dump accepts type `any`;
 // it uses 'davecgh/go-spew'.
 // Dump(…) will not produce output with `t` having set a level above LevelDebug.
+//
+// Secret values are masked: values of type schuko.Secret never reveal
+// themselves, and for dictionaries (map[string]any) and configurations
+// implementing schuko.EnumerableConfiguration, values of secret keys
+// are redacted (see schuko.Redact).
 func With(t Trace) _Dumper {
 	return _Dumper{&t}
 }
@@ -246,11 +337,32 @@ type _Dumper struct {
 // d may not be nil.
 func (d _Dumper) Dump(name string, obj any) {
 	if (*d.tracer).GetTraceLevel() >= LevelDebug {
+		switch x := obj.(type) {
+		case schuko.EnumerableConfiguration:
+			obj = schuko.Redact(x, x.AllSettings())
+		case map[string]any:
+			obj = schuko.Redact(nil, x)
+		}
 		str := spew.Sdump(obj)
 		(*d.tracer).Debugf(name + " = " + str)
 	}
 }
 
+// Mask returns val, or a redacted replacement if key is secret by naming
+// convention (see schuko.IsSecretKey). Adapters call Mask for values passed
+// to P(…), i.e. tracing.P("password", pw) will not reveal pw.
+//
+// Tracers do not know about configurations, therefore keys declared secret
+// otherwise, e.g. with option schuko.Sensitive of a schema or secret by way of
+// interpolation, are not masked. Clients have to mask these themselves, e.g.
+// by checking schuko.IsSecret before passing values to P(…).
+func Mask(key string, val any) any {
+	if schuko.IsSecretKey(key) {
+		return schuko.Redacted
+	}
+	return val
+}
+
 // --- Tracing facade --------------------------------------------------------
 
 // Debugf traces at level LevelDebug to the global default tracer.
@@ -265,6 +377,18 @@ func Infof(msg string, args ...any) {
 	Select("root").Infof(msg, args...)
 }
 
+// Tracef traces at level LevelTrace to the global default tracer.
+// This is part of a global tracing facade.
+func Tracef(msg string, args ...any) {
+	Leveled(Select("root")).Tracef(msg, args...)
+}
+
+// Warnf traces at level LevelWarn to the global default tracer.
+// This is part of a global tracing facade.
+func Warnf(msg string, args ...any) {
+	Leveled(Select("root")).Warnf(msg, args...)
+}
+
 // Errorf traces at level LevelError to the global default tracer.
 // This is part of a global tracing facade.
 func Errorf(msg string, args ...any) {
@@ -279,6 +403,30 @@ func P(k string, v any) Trace {
 	return r.P(k, v)
 }
 
+// Debug traces a structured event at level LevelDebug to the global default
+// tracer (see StructuredTracer). This is part of a global tracing facade.
+func Debug(msg string, kv ...any) {
+	Structured(Select("root")).Debug(msg, kv...)
+}
+
+// Info traces a structured event at level LevelInfo to the global default
+// tracer (see StructuredTracer). This is part of a global tracing facade.
+func Info(msg string, kv ...any) {
+	Structured(Select("root")).Info(msg, kv...)
+}
+
+// Warn traces a structured event at level LevelWarn to the global default
+// tracer (see StructuredTracer). This is part of a global tracing facade.
+func Warn(msg string, kv ...any) {
+	Structured(Select("root")).Warn(msg, kv...)
+}
+
+// Error traces a structured event at level LevelError to the global default
+// tracer (see StructuredTracer). This is part of a global tracing facade.
+func Error(msg string, kv ...any) {
+	Structured(Select("root")).Error(msg, kv...)
+}
+
 // ---------------------------------------------------------------------------
 
 // NoOpTrace returns a void Trace. This is the default for every global tracer.
@@ -295,6 +443,12 @@ type noOpTrace struct{}
 func (nt noOpTrace) Debugf(string, ...any)       {}
 func (nt noOpTrace) Infof(s string, args ...any) {}
 func (nt noOpTrace) Errorf(string, ...any)       {}
+func (nt noOpTrace) Warnf(string, ...any)        {}
+func (nt noOpTrace) Tracef(string, ...any)       {}
+func (nt noOpTrace) Error(string, ...any)        {}
+func (nt noOpTrace) Warn(string, ...any)         {}
+func (nt noOpTrace) Info(string, ...any)         {}
+func (nt noOpTrace) Debug(string, ...any)        {}
 func (nt noOpTrace) SetTraceLevel(TraceLevel)    {}
 func (nt noOpTrace) GetTraceLevel() TraceLevel   { return LevelError }
 func (nt noOpTrace) SetOutput(io.Writer)         {}
@@ -2,8 +2,15 @@ package tracing
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
+	"log/slog"
+	"strings"
 	"testing"
+
+	"github.com/npillmayer/schuko"
+	"github.com/npillmayer/schuko/schukonf/testconfig"
 )
 
 func TestSelectorNoOp(t *testing.T) {
@@ -50,3 +57,163 @@ func (tt *testTracer) SetOutput(w io.Writer) {
 func (tt *testTracer) Select(string) Trace { // testTracer is its own selector
 	return tt
 }
+
+func TestSchema(t *testing.T) {
+	SetTraceSelector(nil)
+	RegisterTraceAdapter("schematest", func() Trace { return noOpTrace{} }, false)
+	conf := testconfig.Conf{
+		"tracing.adapter":     "unknown",
+		"tracing.destination": "file:///tmp/x.log",
+		"tracelevel.root":     "Debug",
+		"tracelevel.db":       "Verbose",
+		"tracing.adaptr":      "schematest",
+	}
+	warnings, err := Schema("tracelevel").Validate(conf)
+	var verr *schuko.ValidationError
+	if !errors.As(err, &verr) || len(verr.Violations) != 2 {
+		t.Fatalf("expected 2 violations, got %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Suggestion != "tracing.adapter" {
+		t.Errorf("expected suggestion for typo, got %v", warnings)
+	}
+	conf["tracing.adapter"] = "schematest"
+	conf["tracelevel.db"] = "info"
+	if _, err = Schema("tracelevel").Validate(conf); err != nil {
+		t.Errorf("expected configuration to be valid, got %v", err)
+	}
+}
+
+// dumpTracer collects debug messages.
+type dumpTracer struct {
+	noOpTrace
+	out bytes.Buffer
+}
+
+func (dt *dumpTracer) Debugf(msg string, args ...any) {
+	dt.out.WriteString(msg)
+}
+
+func (dt *dumpTracer) GetTraceLevel() TraceLevel {
+	return LevelDebug
+}
+
+func TestDumpMasksSecrets(t *testing.T) {
+	dt := &dumpTracer{}
+	With(dt).Dump("settings", map[string]any{
+		"db": map[string]any{"password": "geheim", "user": "admin"},
+	})
+	With(dt).Dump("conf", testconfig.Conf{"api.token": "t0ken", "api.url": "localhost"})
+	With(dt).Dump("creds", struct{ PW schuko.Secret }{"s3cret"})
+	out := dt.out.String()
+	for _, secret := range []string{"geheim", "t0ken", "s3cret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("expected secret %q to be masked in dump, got %q", secret, out)
+		}
+	}
+	if !strings.Contains(out, "admin") || !strings.Contains(out, "localhost") {
+		t.Errorf("expected non-secret values in dump, got %q", out)
+	}
+}
+
+func TestMask(t *testing.T) {
+	if v := Mask("db.password", "geheim"); v != schuko.Redacted {
+		t.Errorf("expected value of secret key to be masked, got %v", v)
+	}
+	if v := Mask("db.user", "admin"); v != "admin" {
+		t.Errorf("expected value of non-secret key to be unchanged, got %v", v)
+	}
+}
+
+func TestTraceLevelFromString(t *testing.T) {
+	for s, l := range map[string]TraceLevel{
+		"Trace": LevelTrace, "debug": LevelDebug, "INFO": LevelInfo,
+		"Warn": LevelWarn, "warning": LevelWarn, "Error": LevelError, "verbose": LevelInfo,
+	} {
+		if level := TraceLevelFromString(s); level != l {
+			t.Errorf("expected %q to be level %s, got %s", s, l, level)
+		}
+	}
+	if !(LevelError < LevelWarn && LevelWarn < LevelInfo && LevelDebug < LevelTrace) {
+		t.Errorf("expected trace levels to be ordered from coarsest to finest")
+	}
+}
+
+// plainTracer implements Trace only, as third-party adapters may do.
+type plainTracer struct {
+	level TraceLevel
+	out   bytes.Buffer
+}
+
+func (pt *plainTracer) Errorf(msg string, args ...any) { fmt.Fprintf(&pt.out, "E:"+msg+";", args...) }
+
+func (pt *plainTracer) Infof(msg string, args ...any) {
+	if pt.level >= LevelInfo {
+		fmt.Fprintf(&pt.out, "I:"+msg+";", args...)
+	}
+}
+
+func (pt *plainTracer) Debugf(msg string, args ...any) {
+	if pt.level >= LevelDebug {
+		fmt.Fprintf(&pt.out, "D:"+msg+";", args...)
+	}
+}
+
+func (pt *plainTracer) P(string, any) Trace        { return pt }
+func (pt *plainTracer) SetTraceLevel(l TraceLevel) { pt.level = l }
+func (pt *plainTracer) GetTraceLevel() TraceLevel  { return pt.level }
+func (pt *plainTracer) SetOutput(io.Writer)        {}
+
+func TestLeveledFallback(t *testing.T) {
+	pt := &plainTracer{level: LevelError}
+	Leveled(pt).Warnf("w1")
+	pt.SetTraceLevel(LevelWarn)
+	Leveled(pt).Warnf("w2")
+	Leveled(pt).Tracef("t1")
+	pt.SetTraceLevel(LevelInfo)
+	Leveled(pt).Warnf("w3")
+	pt.SetTraceLevel(LevelTrace)
+	Leveled(pt).Tracef("t2")
+	if out := pt.out.String(); out != "E:w2;I:w3;D:t2;" {
+		t.Errorf("expected fallbacks to Errorf, Infof and Debugf, got %q", out)
+	}
+	if _, ok := Leveled(noOpTrace{}).(noOpTrace); !ok {
+		t.Errorf("expected tracers implementing LevelTracer to be returned as is")
+	}
+}
+
+func TestFormatAttrs(t *testing.T) {
+	for _, c := range []struct {
+		kv   []any
+		want string
+	}{
+		{nil, "msg"},
+		{[]any{"key", "users/42", "size", 1024}, "msg key=users/42 size=1024"},
+		{[]any{"reason", "not found", "empty", ""}, `msg reason="not found" empty=""`},
+		{[]any{slog.Int("n", 7), "db.password", "geheim"}, "msg n=7 db.password=******"},
+		{[]any{slog.Group("req", "id", 7, "path", "/")}, "msg req.id=7 req.path=/"},
+		{[]any{slog.Group("db", "user", "app", "password", "geheim")}, "msg db.user=app db.password=******"},
+		{[]any{slog.Group("a", slog.Group("b", "api_token", "t0k3n"))}, "msg a.b.api_token=******"},
+		{[]any{slog.Group("credentials", "user", "app")}, "msg credentials=******"},
+		{[]any{"dangling"}, "msg !BADKEY=dangling"},
+		{[]any{42, "x", 1}, "msg !BADKEY=42 x=1"},
+	} {
+		if got := FormatAttrs("msg", c.kv...); got != c.want {
+			t.Errorf("expected %q, got %q", c.want, got)
+		}
+	}
+}
+
+func TestStructuredFallback(t *testing.T) {
+	pt := &plainTracer{level: LevelInfo}
+	Structured(pt).Info("cache miss", "key", "k1", "size", 3)
+	Structured(pt).Warn("almost full")
+	Structured(pt).Debug("debug", slog.Bool("hit", false))
+	pt.SetTraceLevel(LevelWarn)
+	Structured(pt).Warn("full")
+	if out := pt.out.String(); out != "I:cache miss key=k1 size=3;I:almost full;E:full;" {
+		t.Errorf("expected fallbacks to Infof and Errorf, got %q", out)
+	}
+	if _, ok := Structured(noOpTrace{}).(noOpTrace); !ok {
+		t.Errorf("expected tracers implementing StructuredTracer to be returned as is")
+	}
+}
@@ -0,0 +1,243 @@
+package schuko
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TypedConfiguration is an optional extension of Configuration. Adapters
+// implement it to offer access to configuration values of types beyond
+// strings, integers and booleans.
+//
+// All adapters of this module use the conversion functions of this package
+// (ToFloat64, ToDuration, etc.), so swapping the configuration backend does not
+// change how a value is interpreted.
+type TypedConfiguration interface {
+	Configuration
+	GetFloat64(key string) float64          // get config value as float
+	GetDuration(key string) time.Duration   // get config value as duration
+	GetTime(key string) time.Time           // get config value as point in time
+	GetStringSlice(key string) []string     // get config value as list of strings
+	GetStringMap(key string) map[string]any // get config value as dictionary
+}
+
+// Typed returns a TypedConfiguration for conf. If conf implements
+// TypedConfiguration, it is returned unchanged. Otherwise the typed getters
+// will convert the string representation of a value, as returned by
+// conf.GetString.
+func Typed(conf Configuration) TypedConfiguration {
+	if tc, ok := conf.(TypedConfiguration); ok {
+		return tc
+	}
+	return typedWrapper{conf}
+}
+
+type typedWrapper struct {
+	Configuration
+}
+
+func (w typedWrapper) GetFloat64(key string) float64 {
+	f, _ := ToFloat64(w.GetString(key))
+	return f
+}
+
+func (w typedWrapper) GetDuration(key string) time.Duration {
+	d, _ := ToDuration(w.GetString(key))
+	return d
+}
+
+func (w typedWrapper) GetTime(key string) time.Time {
+	t, _ := ToTime(w.GetString(key))
+	return t
+}
+
+func (w typedWrapper) GetStringSlice(key string) []string {
+	if !w.IsSet(key) {
+		return nil
+	}
+	l, _ := ToStringSlice(w.GetString(key))
+	return l
+}
+
+func (w typedWrapper) GetStringMap(key string) map[string]any {
+	if !w.IsSet(key) {
+		return nil
+	}
+	m, _ := ToStringMap(w.GetString(key))
+	return m
+}
+
+// --- Conversion rules ------------------------------------------------------
+
+// ToFloat64 converts a configuration value to a float. It accepts all Go number
+// types and strings in a format understood by strconv.ParseFloat.
+func ToFloat64(v any) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case float32:
+		return float64(x), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot convert %q to float: %w", x, err)
+		}
+		return f, nil
+	}
+	if n, ok := toInt64(v); ok {
+		return float64(n), nil
+	}
+	return 0, fmt.Errorf("cannot convert %T to float", v)
+}
+
+// ToDuration converts a configuration value to a time.Duration.
+// Strings are parsed with time.ParseDuration, e.g. "1.5s" or "2h45m".
+// Plain numbers, and strings consisting of a plain number, are interpreted
+// as seconds.
+func ToDuration(v any) (time.Duration, error) {
+	switch x := v.(type) {
+	case time.Duration:
+		return x, nil
+	case string:
+		s := strings.TrimSpace(x)
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return time.Duration(f * float64(time.Second)), nil
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("cannot convert %q to duration: %w", x, err)
+		}
+		return d, nil
+	case float64:
+		return time.Duration(x * float64(time.Second)), nil
+	case float32:
+		return time.Duration(float64(x) * float64(time.Second)), nil
+	}
+	if n, ok := toInt64(v); ok {
+		return time.Duration(n) * time.Second, nil
+	}
+	return 0, fmt.Errorf("cannot convert %T to duration", v)
+}
+
+// TimeLayouts are the formats ToTime will try, in order, to parse a string
+// as a point in time.
+var TimeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ToTime converts a configuration value to a time.Time. Strings have to match
+// one of TimeLayouts. Integers are interpreted as Unix time in seconds.
+func ToTime(v any) (time.Time, error) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, nil
+	case string:
+		s := strings.TrimSpace(x)
+		for _, layout := range TimeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("cannot convert %q to time: unknown format", x)
+	}
+	if n, ok := toInt64(v); ok {
+		return time.Unix(n, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("cannot convert %T to time", v)
+}
+
+// ToStringSlice converts a configuration value to a list of strings.
+// Strings are split at commas, with surrounding white space removed from
+// every item. An empty string results in an empty list.
+// Lists of other types are converted item by item.
+func ToStringSlice(v any) ([]string, error) {
+	switch x := v.(type) {
+	case []string:
+		return x, nil
+	case []any:
+		l := make([]string, len(x))
+		for i, item := range x {
+			l[i] = fmt.Sprintf("%v", item)
+		}
+		return l, nil
+	case string:
+		if strings.TrimSpace(x) == "" {
+			return []string{}, nil
+		}
+		l := strings.Split(x, ",")
+		for i, item := range l {
+			l[i] = strings.TrimSpace(item)
+		}
+		return l, nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to list of strings", v)
+}
+
+// ToStringMap converts a configuration value to a dictionary.
+// Strings are interpreted as comma-separated "key=value" pairs, e.g.
+// "host=localhost, port=8080". An empty string results in an empty map.
+func ToStringMap(v any) (map[string]any, error) {
+	switch x := v.(type) {
+	case map[string]any:
+		return x, nil
+	case map[string]string:
+		m := make(map[string]any, len(x))
+		for k, val := range x {
+			m[k] = val
+		}
+		return m, nil
+	case map[any]any:
+		m := make(map[string]any, len(x))
+		for k, val := range x {
+			m[fmt.Sprintf("%v", k)] = val
+		}
+		return m, nil
+	case string:
+		m := make(map[string]any)
+		if strings.TrimSpace(x) == "" {
+			return m, nil
+		}
+		for _, pair := range strings.Split(x, ",") {
+			k, val, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("cannot convert %q to map: missing '=' in %q", x, pair)
+			}
+			m[strings.TrimSpace(k)] = strings.TrimSpace(val)
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("cannot convert %T to map", v)
+}
+
+func toInt64(v any) (int64, bool) {
+	switch x := v.(type) {
+	case int:
+		return int64(x), true
+	case int8:
+		return int64(x), true
+	case int16:
+		return int64(x), true
+	case int32:
+		return int64(x), true
+	case int64:
+		return x, true
+	case uint:
+		return int64(x), true
+	case uint8:
+		return int64(x), true
+	case uint16:
+		return int64(x), true
+	case uint32:
+		return int64(x), true
+	case uint64:
+		return int64(x), true
+	}
+	return 0, false
+}
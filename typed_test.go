@@ -0,0 +1,84 @@
+package schuko
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToDuration(t *testing.T) {
+	for _, x := range []struct {
+		v any
+		d time.Duration
+	}{
+		{"1.5s", 1500 * time.Millisecond},
+		{"2h45m", 2*time.Hour + 45*time.Minute},
+		{"30", 30 * time.Second},
+		{10, 10 * time.Second},
+		{0.5, 500 * time.Millisecond},
+		{time.Minute, time.Minute},
+	} {
+		d, err := ToDuration(x.v)
+		if err != nil {
+			t.Errorf("unexpected error for %v: %v", x.v, err)
+		} else if d != x.d {
+			t.Errorf("expected %v to convert to %v, got %v", x.v, x.d, d)
+		}
+	}
+	if _, err := ToDuration("fast"); err == nil {
+		t.Errorf("expected \"fast\" not to convert to a duration")
+	}
+}
+
+func TestToStringSlice(t *testing.T) {
+	l, err := ToStringSlice(" a, b ,c")
+	if err != nil || len(l) != 3 || l[0] != "a" || l[1] != "b" || l[2] != "c" {
+		t.Errorf("expected [a b c], got %v (%v)", l, err)
+	}
+	l, err = ToStringSlice([]any{"x", 7})
+	if err != nil || len(l) != 2 || l[1] != "7" {
+		t.Errorf("expected [x 7], got %v (%v)", l, err)
+	}
+}
+
+func TestToStringMap(t *testing.T) {
+	m, err := ToStringMap("host=localhost, port=8080")
+	if err != nil || m["host"] != "localhost" || m["port"] != "8080" {
+		t.Errorf("expected host and port to be set, got %v (%v)", m, err)
+	}
+	if _, err = ToStringMap("host"); err == nil {
+		t.Errorf("expected error for missing '='")
+	}
+}
+
+func TestToTimeAndFloat(t *testing.T) {
+	tm, err := ToTime("2021-03-04")
+	if err != nil || tm.Year() != 2021 || tm.Month() != time.March || tm.Day() != 4 {
+		t.Errorf("expected 2021-03-04, got %v (%v)", tm, err)
+	}
+	f, err := ToFloat64(" 0.25")
+	if err != nil || f != 0.25 {
+		t.Errorf("expected 0.25, got %v (%v)", f, err)
+	}
+}
+
+type plainConf map[string]string
+
+func (c plainConf) InitDefaults()               {}
+func (c plainConf) IsSet(key string) bool       { _, ok := c[key]; return ok }
+func (c plainConf) GetString(key string) string { return c[key] }
+func (c plainConf) GetInt(key string) int       { return 0 }
+func (c plainConf) GetBool(key string) bool     { return false }
+
+func TestTypedWrapper(t *testing.T) {
+	conf := plainConf{"timeout": "1.5s", "list": "a,b"}
+	tc := Typed(conf)
+	if d := tc.GetDuration("timeout"); d != 1500*time.Millisecond {
+		t.Errorf("expected timeout of 1.5s, got %v", d)
+	}
+	if l := tc.GetStringSlice("list"); len(l) != 2 {
+		t.Errorf("expected list of 2, got %v", l)
+	}
+	if l := tc.GetStringSlice("missing"); l != nil {
+		t.Errorf("expected nil list for missing key, got %v", l)
+	}
+}